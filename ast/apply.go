@@ -0,0 +1,325 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package ast
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// An ApplyFunc is invoked by Apply for each node n, even if n is the root, before
+// and/or after the node's children, using a Cursor describing the current
+// node and providing operations on it.
+//
+// The return value of ApplyFunc controls the syntax tree traversal. See Apply
+// for details.
+type ApplyFunc func(*Cursor) bool
+
+// Apply traverses a syntax tree recursively, starting with root, and calling
+// pre and post for each node as described below. Apply returns the syntax tree,
+// possibly modified.
+//
+// If pre is not nil, it is called for each node before the node's children are
+// traversed (pre-order). If pre returns false, no children are traversed, and
+// post is not called for that node.
+//
+// If post is not nil, and a prior call of pre didn't return false, post is
+// called for each node after its children are traversed (post-order). If post
+// returns false, traversal is terminated and Apply returns immediately.
+//
+// Nil children are not visited. Children are traversed in the same order as
+// by Walk. The nodes replacing or inserted by the cursor are not walked.
+func Apply(root any, pre, post ApplyFunc) (result any) {
+	parent := &struct{ Node any }{root}
+
+	defer func() {
+		if r := recover(); r != nil && r != abort {
+			panic(r)
+		}
+
+		result = parent.Node
+	}()
+
+	a := &application{pre: pre, post: post}
+	a.apply(parent, "Node", nil, root)
+
+	return
+}
+
+var abort = new(int)
+
+// A Cursor describes a node encountered during Apply. Information about the
+// node and its parent is available from the Node, Parent, Name and Index
+// methods.
+//
+// The methods Replace, Delete, InsertBefore and InsertAfter can be used to
+// change the AST without disrupting Apply. Delete and the insert methods are
+// only allowed on nodes of lists.
+type Cursor struct {
+	parent any
+	name   string
+	iter   *iterator
+	node   any
+}
+
+// Node returns the current node.
+func (c *Cursor) Node() any { return c.node }
+
+// Parent returns the parent of the current node.
+func (c *Cursor) Parent() any { return c.parent }
+
+// Name returns the name of the parent field that contains the current node.
+func (c *Cursor) Name() string { return c.name }
+
+// Index reports the index of the current node in the list of the parent, or
+// a value < 0 if the current node is not part of a list.
+func (c *Cursor) Index() int {
+	if c.iter != nil {
+		return c.iter.index
+	}
+
+	return -1
+}
+
+func (c *Cursor) field() reflect.Value {
+	return reflect.Indirect(reflect.ValueOf(c.parent)).FieldByName(c.name)
+}
+
+// Replace replaces the current node with n. Nodes that are stored by value,
+// such as the type of a field, are replaced with a copy of n.
+func (c *Cursor) Replace(n any) {
+	v := c.field()
+	if i := c.Index(); i >= 0 {
+		v = v.Index(i)
+	}
+
+	value := reflect.ValueOf(n)
+	if v.Kind() == reflect.Struct {
+		value = value.Elem()
+	}
+
+	v.Set(value)
+}
+
+// Delete deletes the current node from its containing list.
+func (c *Cursor) Delete() {
+	i := c.list("Delete")
+
+	v := c.field()
+	l := v.Len()
+
+	reflect.Copy(v.Slice(i, l), v.Slice(i+1, l))
+	v.Index(l - 1).Set(reflect.Zero(v.Type().Elem()))
+	v.SetLen(l - 1)
+
+	c.iter.step--
+}
+
+// InsertAfter inserts n after the current node in its containing list. The
+// inserted node is not walked by Apply.
+func (c *Cursor) InsertAfter(n any) {
+	i := c.list("InsertAfter")
+
+	v := c.field()
+	v.Set(reflect.Append(v, reflect.Zero(v.Type().Elem())))
+	l := v.Len()
+
+	reflect.Copy(v.Slice(i+2, l), v.Slice(i+1, l))
+	v.Index(i + 1).Set(reflect.ValueOf(n))
+
+	c.iter.step++
+}
+
+// InsertBefore inserts n before the current node in its containing list. The
+// inserted node is not walked by Apply.
+func (c *Cursor) InsertBefore(n any) {
+	i := c.list("InsertBefore")
+
+	v := c.field()
+	v.Set(reflect.Append(v, reflect.Zero(v.Type().Elem())))
+	l := v.Len()
+
+	reflect.Copy(v.Slice(i+1, l), v.Slice(i, l))
+	v.Index(i).Set(reflect.ValueOf(n))
+
+	c.iter.index++
+}
+
+func (c *Cursor) list(method string) int {
+	i := c.Index()
+	if i < 0 {
+		panic(fmt.Sprintf("ast: %s node not contained in list", method))
+	}
+
+	return i
+}
+
+type iterator struct {
+	index, step int
+}
+
+type application struct {
+	pre, post ApplyFunc
+	cursor    Cursor
+	iter      iterator
+}
+
+func (a *application) apply(parent any, name string, iter *iterator, n any) {
+	if v := reflect.ValueOf(n); !v.IsValid() || (v.Kind() == reflect.Pointer && v.IsNil()) {
+		return
+	}
+
+	saved := a.cursor
+	a.cursor = Cursor{parent: parent, name: name, iter: iter, node: n}
+
+	if a.pre != nil && !a.pre(&a.cursor) {
+		a.cursor = saved
+
+		return
+	}
+
+	switch n := n.(type) {
+	case *Program:
+		a.applyList(n, "Nodes")
+	case *Node:
+		a.apply(n, "Collection", nil, n.Collection)
+		a.apply(n, "Function", nil, n.Function)
+	case *Collection:
+		a.apply(n, "Doc", nil, n.Doc)
+		a.applyList(n, "Decorators")
+		a.applyList(n, "Items")
+	case *Item:
+		a.applyList(n, "Decorators")
+		a.apply(n, "Function", nil, n.Function)
+		a.apply(n, "Field", nil, n.Field)
+		a.apply(n, "Index", nil, n.Index)
+	case *Field:
+		a.apply(n, "Doc", nil, n.Doc)
+		a.apply(n, "Type", nil, &n.Type)
+		a.apply(n, "Comment", nil, n.Comment)
+	case *Index:
+		a.apply(n, "Doc", nil, n.Doc)
+		a.applyList(n, "Fields")
+		a.apply(n, "Comment", nil, n.Comment)
+	case *Function:
+		a.apply(n, "Doc", nil, n.Doc)
+		a.applyList(n, "Parameters")
+
+		if !n.ReturnType.IsZero() {
+			a.apply(n, "ReturnType", nil, &n.ReturnType)
+		}
+
+		a.applyList(n, "Statements")
+	case *CommentGroup:
+		a.applyList(n, "List")
+	case *Type:
+		a.apply(n, "Map", nil, n.Map)
+		a.applyList(n, "Object")
+	case *Map:
+		a.apply(n, "Value", nil, &n.Value)
+	case *Statement:
+		a.apply(n, "Compound", nil, n.Compound)
+		a.apply(n, "Simple", nil, n.Simple)
+	case *CompoundStatement:
+		a.apply(n, "If", nil, n.If)
+		a.apply(n, "While", nil, n.While)
+		a.apply(n, "For", nil, n.For)
+		a.apply(n, "Block", nil, n.Block)
+	case *Block:
+		a.applyList(n, "Statements")
+	case *SimpleStatement:
+		a.apply(n, "Small", nil, n.Small)
+	case *SmallStatement:
+		a.apply(n, "Return", nil, n.Return)
+		a.apply(n, "Throw", nil, n.Throw)
+		a.apply(n, "Let", nil, n.Let)
+		a.apply(n, "Expression", nil, n.Expression)
+	case *Return:
+		a.apply(n, "Value", nil, n.Value)
+	case *StatementsOrSimple:
+		a.applyList(n, "Statements")
+		a.apply(n, "Compound", nil, n.Compound)
+		a.apply(n, "Simple", nil, n.Simple)
+	case *If:
+		a.apply(n, "Condition", nil, n.Condition)
+		a.apply(n, "Statement", nil, n.Statement)
+		a.apply(n, "Else", nil, n.Else)
+	case *While:
+		a.apply(n, "Condition", nil, n.Condition)
+		a.applyList(n, "Statements")
+	case *Let:
+		a.apply(n, "Expression", nil, n.Expression)
+	case *For:
+		a.apply(n, "Initial", nil, n.Initial)
+		a.apply(n, "Condition", nil, n.Condition)
+		a.apply(n, "Post", nil, n.Post)
+		a.applyList(n, "Statements")
+	case *ForInitial:
+		a.apply(n, "Let", nil, n.Let)
+		a.apply(n, "Expression", nil, n.Expression)
+	case *Expression:
+		a.apply(n, "Unary", nil, n.Unary)
+		a.apply(n, "Update", nil, n.Update)
+		a.apply(n, "Binary", nil, n.Binary)
+		a.apply(n, "Call", nil, n.Call)
+		a.apply(n, "Member", nil, n.Member)
+		a.apply(n, "Index", nil, n.Index)
+		a.apply(n, "Array", nil, n.Array)
+		a.apply(n, "Object", nil, n.Object)
+		a.apply(n, "Value", nil, n.Value)
+	case *UnaryExpr:
+		a.apply(n, "Operand", nil, n.Operand)
+	case *UpdateExpr:
+		a.apply(n, "Operand", nil, n.Operand)
+	case *BinaryExpr:
+		a.apply(n, "Left", nil, n.Left)
+		a.apply(n, "Right", nil, n.Right)
+	case *CallExpr:
+		a.apply(n, "Callee", nil, n.Callee)
+		a.applyList(n, "Arguments")
+	case *MemberExpr:
+		a.apply(n, "Object", nil, n.Object)
+	case *IndexExpr:
+		a.apply(n, "Object", nil, n.Object)
+		a.apply(n, "Index", nil, n.Index)
+	case *ArrayLit:
+		a.applyList(n, "Elements")
+	case *ObjectLit:
+		a.applyList(n, "Properties")
+	case *Property:
+		a.apply(n, "Value", nil, n.Value)
+	case *Value:
+		a.apply(n, "Sub", nil, n.Sub)
+	}
+
+	if a.post != nil && !a.post(&a.cursor) {
+		panic(abort)
+	}
+
+	a.cursor = saved
+}
+
+func (a *application) applyList(parent any, name string) {
+	saved := a.iter
+	a.iter.index = 0
+
+	for {
+		// must reload parent.name each time, since cursor modifications
+		// might have reallocated the list
+		v := reflect.Indirect(reflect.ValueOf(parent)).FieldByName(name)
+		if a.iter.index >= v.Len() {
+			break
+		}
+
+		a.iter.step = 1
+		a.apply(parent, name, &a.iter, v.Index(a.iter.index).Interface())
+		a.iter.index += a.iter.step
+	}
+
+	a.iter = saved
+}
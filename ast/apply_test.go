@@ -0,0 +1,146 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package ast_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/durudex/go-polylang/ast"
+)
+
+func TestApply_Replace(t *testing.T) {
+	prog := parseProgram(t, "function f(a: number) { return a + a; }")
+
+	ast.Apply(prog, func(c *ast.Cursor) bool {
+		if value, ok := c.Node().(*ast.Value); ok && value.Ident != nil && *value.Ident == "a" {
+			c.Replace(&ast.Value{Number: &ast.NumberLit{Raw: "1"}})
+		}
+
+		return true
+	}, nil)
+
+	zeroPos(prog)
+
+	want := &ast.Statement{
+		Simple: &ast.SimpleStatement{
+			Small: &ast.SmallStatement{
+				Return: &ast.Return{Value: binary(number(1), ast.Add, number(1))},
+			},
+		},
+	}
+
+	if got := prog.Nodes[0].Function.Statements[0]; !reflect.DeepEqual(got, want) {
+		t.Fatal("error: statement does not match")
+	}
+}
+
+func TestApply_ReplaceType(t *testing.T) {
+	prog := parseProgram(t, "function f(a: number): number {}")
+
+	ast.Apply(prog, func(c *ast.Cursor) bool {
+		if typ, ok := c.Node().(*ast.Type); ok && typ.Basic == ast.Number {
+			c.Replace(&ast.Type{Basic: ast.String})
+		}
+
+		return true
+	}, nil)
+
+	fn := prog.Nodes[0].Function
+	if fn.Parameters[0].Type.Basic != ast.String || fn.ReturnType.Basic != ast.String {
+		t.Fatal("error: types are not replaced")
+	}
+}
+
+func TestApply_List(t *testing.T) {
+	prog := parseProgram(t, "function f() { a; b; c; }")
+
+	ast.Apply(prog, func(c *ast.Cursor) bool {
+		stmt, ok := c.Node().(*ast.Statement)
+		if !ok {
+			return true
+		}
+
+		switch *stmt.Simple.Small.Expression.Value.Ident {
+		case "a":
+			c.InsertBefore(exprStatement("x"))
+		case "b":
+			c.Delete()
+		case "c":
+			c.InsertAfter(exprStatement("y"))
+		}
+
+		return false
+	}, nil)
+
+	var got []string
+	for _, stmt := range prog.Nodes[0].Function.Statements {
+		got = append(got, *stmt.Simple.Small.Expression.Value.Ident)
+	}
+
+	if want := "x a c y"; strings.Join(got, " ") != want {
+		t.Fatalf("error: statements do not match: %v", got)
+	}
+}
+
+func TestApply_Root(t *testing.T) {
+	root := ident("a")
+
+	got := ast.Apply(root, func(c *ast.Cursor) bool {
+		if c.Parent() != nil && c.Index() < 0 && c.Node() == root {
+			c.Replace(ident("b"))
+		}
+
+		return false
+	}, nil)
+
+	if got.(*ast.Expression) == root {
+		t.Fatal("error: root is not replaced")
+	}
+}
+
+func TestApply_Abort(t *testing.T) {
+	var values int
+
+	ast.Apply(parseProgram(t, "function f() { a; b; }"), nil, func(c *ast.Cursor) bool {
+		if _, ok := c.Node().(*ast.Value); ok {
+			values++
+
+			return false
+		}
+
+		return true
+	})
+
+	if values != 1 {
+		t.Fatalf("error: applied to %d values", values)
+	}
+}
+
+func TestCursor_DeletePanic(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("error: expected panic")
+		}
+	}()
+
+	ast.Apply(ident("a"), func(c *ast.Cursor) bool {
+		c.Delete()
+
+		return true
+	}, nil)
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func exprStatement(name string) *ast.Statement {
+	return &ast.Statement{
+		Simple: &ast.SimpleStatement{Small: &ast.SmallStatement{Expression: ident(name)}},
+	}
+}
@@ -7,11 +7,20 @@
 
 package ast
 
+import "github.com/alecthomas/participle/v2/lexer"
+
 type Program struct {
-	Nodes []*Node `parser:"@@*" json:"nodes,omitempty"`
+	Pos    lexer.Position
+	EndPos lexer.Position
+
+	Nodes    []*Node         `parser:"@@*" json:"nodes,omitempty"`
+	Comments []*CommentGroup `parser:""    json:"comments,omitempty"`
 }
 
 type Node struct {
+	Pos    lexer.Position
+	EndPos lexer.Position
+
 	Collection *Collection `parser:"@@"   json:"collection,omitempty"`
 	Function   *Function   `parser:"| @@" json:"function,omitempty"`
 }
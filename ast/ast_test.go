@@ -12,10 +12,43 @@ import (
 	"testing"
 
 	"github.com/durudex/go-polylang"
+	"github.com/durudex/go-polylang/ast"
 
 	"github.com/alecthomas/participle/v2"
+	"github.com/alecthomas/participle/v2/lexer"
 )
 
+var positionType = reflect.TypeOf(lexer.Position{})
+
+// zeroPos resets every source position reachable from v, so that parsed nodes
+// can be compared with expectations that do not spell out positions.
+func zeroPos(v any) { zeroPosValue(reflect.ValueOf(v)) }
+
+func zeroPosValue(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			zeroPosValue(v.Elem())
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			zeroPosValue(v.Index(i))
+		}
+	case reflect.Struct:
+		if v.Type() == positionType {
+			if v.CanSet() {
+				v.Set(reflect.Zero(positionType))
+			}
+
+			return
+		}
+
+		for i := 0; i < v.NumField(); i++ {
+			zeroPosValue(v.Field(i))
+		}
+	}
+}
+
 var CommentTests = map[string]struct {
 	code string
 	want string
@@ -70,3 +103,66 @@ func BenchmarkComment(b *testing.B) {
 		})
 	}
 }
+
+var PositionTests = map[string]struct {
+	code   string
+	pos    lexer.Position
+	endPos lexer.Position
+}{
+	"Collection": {
+		code:   "\n  collection Test { id: string; }\n",
+		pos:    lexer.Position{Offset: 3, Line: 2, Column: 3},
+		endPos: lexer.Position{Offset: 35, Line: 3, Column: 1},
+	},
+	"Function": {
+		code:   "function test() {}",
+		pos:    lexer.Position{Offset: 0, Line: 1, Column: 1},
+		endPos: lexer.Position{Offset: 18, Line: 1, Column: 19},
+	},
+}
+
+func TestPosition(t *testing.T) {
+	parser := participle.MustBuild[ast.Program](
+		participle.Lexer(polylang.Lexer),
+	)
+
+	for name, test := range PositionTests {
+		t.Run(name, func(t *testing.T) {
+			got, err := parser.ParseString("", test.code)
+			if err != nil {
+				t.Fatal("error: parsing polylang code: ", err)
+			}
+
+			node := got.Nodes[0]
+
+			if !reflect.DeepEqual(node.Pos, test.pos) {
+				t.Fatal("error: start position does not match: ", node.Pos)
+			}
+
+			if !reflect.DeepEqual(node.EndPos, test.endPos) {
+				t.Fatal("error: end position does not match: ", node.EndPos)
+			}
+		})
+	}
+}
+
+func TestPosition_Nested(t *testing.T) {
+	parser := participle.MustBuild[ast.Collection](
+		participle.Lexer(polylang.Lexer),
+	)
+
+	got, err := parser.ParseString("test.polylang", "collection Test {\n  id: string;\n}")
+	if err != nil {
+		t.Fatal("error: parsing polylang code: ", err)
+	}
+
+	want := lexer.Position{Filename: "test.polylang", Offset: 20, Line: 2, Column: 3}
+
+	if !reflect.DeepEqual(got.Items[0].Field.Pos, want) {
+		t.Fatal("error: field position does not match: ", got.Items[0].Field.Pos)
+	}
+
+	if !reflect.DeepEqual(got.Items[0].Field.Type.Pos.Offset, 24) {
+		t.Fatal("error: type position does not match: ", got.Items[0].Field.Type.Pos)
+	}
+}
@@ -7,35 +7,79 @@
 
 package ast
 
+import "github.com/alecthomas/participle/v2/lexer"
+
 type Collection struct {
+	Pos    lexer.Position
+	EndPos lexer.Position
+	Doc    *CommentGroup
+
 	Decorators []*Decorator `parser:"( @@* )?"`
 	Name       string       `parser:"'collection' @Ident"`
 	Items      []*Item      `parser:"'{' @@* '}'"`
 }
 
 type Item struct {
-	Decorators []*Decorator `parser:"( @@* )?"`
-	Function   *Function    `parser:"@@"`
+	Pos    lexer.Position
+	EndPos lexer.Position
+
+	Decorators []*Decorator `parser:"@@*"`
+	Function   *Function    `parser:"( @@"`
 	Field      *Field       `parser:"| @@ ';'"`
-	Index      *Index       `parser:"| @@ ';'"`
+	Index      *Index       `parser:"| @@ ';' )"`
 }
 
 type Field struct {
+	Pos     lexer.Position
+	EndPos  lexer.Position
+	Doc     *CommentGroup
+	Comment *CommentGroup
+
 	Name     string `parser:"@Ident"`
 	Optional bool   `parser:"@'?'?"`
 	Type     Type   `parser:"':' @@"`
 }
 
+// LookupField returns the field at the path of names, which descends into the
+// fields of object types, or nil if there is no such field.
+func LookupField(fields []*Field, path []string) *Field {
+	for _, field := range fields {
+		if field.Name != path[0] {
+			continue
+		}
+
+		if len(path) == 1 {
+			return field
+		}
+
+		return LookupField(field.Type.Object, path[1:])
+	}
+
+	return nil
+}
+
 type Index struct {
+	Pos     lexer.Position
+	EndPos  lexer.Position
+	Doc     *CommentGroup
+	Comment *CommentGroup
+
 	Fields []*IndexField `parser:"'@' 'index' '(' ( @@ ( ',' @@ )* )? ')'"`
 }
 
 type IndexField struct {
-	Name  string `parser:"( '[' )? ( @Ident )"`
-	Order Order  `parser:"( ',' @@ ']' )?"`
+	Pos    lexer.Position
+	EndPos lexer.Position
+
+	Name  FieldPath `parser:"( '[' )? @@"`
+	Order Order     `parser:"( ',' @@ ']' )?"`
 }
 
 type Function struct {
+	Pos    lexer.Position
+	EndPos lexer.Position
+	Doc    *CommentGroup
+
 	Name       string       `parser:"( 'function' )? @Ident '('"`
 	Parameters []*Field     `parser:"( @@ ( ',' @@ )* )? ')'"`
 	ReturnType Type         `parser:"( ':' @@ )?"`
@@ -75,38 +75,18 @@ var CollectionTests = map[string]struct {
 							{
 								Simple: &ast.SimpleStatement{
 									Small: &ast.SmallStatement{
-										Expression: &ast.Expression{
-											Left: &ast.Value{
-												Ident: func(v string) *string {
-													return &v
-												}("this.id"),
-											},
-											Operator: ast.Assign,
-											Right: &ast.Value{
-												Ident: func(v string) *string {
-													return &v
-												}("id"),
-											},
-										},
+										Expression: binary(
+											member(ident("this"), "id"), ast.Assign, ident("id"),
+										),
 									},
 								},
 							},
 							{
 								Simple: &ast.SimpleStatement{
 									Small: &ast.SmallStatement{
-										Expression: &ast.Expression{
-											Left: &ast.Value{
-												Ident: func(v string) *string {
-													return &v
-												}("this.title"),
-											},
-											Operator: ast.Assign,
-											Right: &ast.Value{
-												Ident: func(v string) *string {
-													return &v
-												}("title"),
-											},
-										},
+										Expression: binary(
+											member(ident("this"), "title"), ast.Assign, ident("title"),
+										),
 									},
 								},
 							},
@@ -120,14 +100,7 @@ var CollectionTests = map[string]struct {
 							{
 								Simple: &ast.SimpleStatement{
 									Small: &ast.SmallStatement{
-										Expression: &ast.Expression{
-											Left: &ast.Value{
-												Ident: func(v string) *string {
-													return &v
-												}("selfdestruct"),
-											},
-											Right: &ast.Value{},
-										},
+										Expression: call(ident("selfdestruct")),
 									},
 								},
 							},
@@ -157,6 +130,8 @@ func TestCollection(t *testing.T) {
 				t.Fatal("error: parsing polylang code: ", err)
 			}
 
+			zeroPos(got)
+
 			if !reflect.DeepEqual(got, test.want) {
 				t.Fatal("error: collection does not match")
 			}
@@ -217,6 +192,8 @@ func TestField(t *testing.T) {
 				t.Fatal("error: parsing polylang code: ", err)
 			}
 
+			zeroPos(got)
+
 			if !reflect.DeepEqual(got, test.want) {
 				t.Fatal("error: field does not match")
 			}
@@ -238,6 +215,22 @@ func BenchmarkField(b *testing.B) {
 	}
 }
 
+func TestLookupField(t *testing.T) {
+	name := &ast.Field{Name: "name", Type: ast.Type{Basic: ast.String}}
+	fields := []*ast.Field{
+		{Name: "id", Type: ast.Type{Basic: ast.String}},
+		{Name: "info", Type: ast.Type{Object: []*ast.Field{name}}},
+	}
+
+	if got := ast.LookupField(fields, []string{"info", "name"}); got != name {
+		t.Fatal("error: nested field does not match")
+	}
+
+	if got := ast.LookupField(fields, []string{"id", "name"}); got != nil {
+		t.Fatal("error: expected no field")
+	}
+}
+
 var IndexTests = map[string]struct {
 	code string
 	want *ast.Index
@@ -260,6 +253,8 @@ func TestIndex(t *testing.T) {
 				t.Fatal("error: parsing polylang code: ", err)
 			}
 
+			zeroPos(got)
+
 			if !reflect.DeepEqual(got, test.want) {
 				t.Fatal("error: index does not match")
 			}
@@ -292,6 +287,13 @@ var IndexFieldTests = map[string]struct {
 			Order: ast.Desc,
 		},
 	},
+	"Path": {
+		code: "[info.author, asc]",
+		want: &ast.IndexField{
+			Name:  "info.author",
+			Order: ast.Asc,
+		},
+	},
 	"Simple Field": {
 		code: "id",
 		want: &ast.IndexField{
@@ -313,6 +315,8 @@ func TestIndexField(t *testing.T) {
 				t.Fatal("error: parsing polylang code: ", err)
 			}
 
+			zeroPos(got)
+
 			if !reflect.DeepEqual(got, test.want) {
 				t.Fatal("error: index field does not match")
 			}
@@ -367,6 +371,8 @@ func TestFunction(t *testing.T) {
 				t.Fatal("error: parsing polylang code: ", err)
 			}
 
+			zeroPos(got)
+
 			if !reflect.DeepEqual(got, test.want) {
 				t.Fatal("error: function does not match")
 			}
@@ -0,0 +1,69 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package ast
+
+import (
+	"strings"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+// Comment is a single line or block comment, with its markers.
+type Comment struct {
+	Pos    lexer.Position
+	EndPos lexer.Position
+
+	Text string
+}
+
+// CommentGroup is a sequence of comments with no other tokens and no empty
+// lines between them.
+type CommentGroup struct {
+	List []*Comment
+}
+
+// Text returns the text of the comment group without the comment markers, the
+// first space of line comments, and leading and trailing empty lines. Lines
+// are ended by newlines.
+func (g *CommentGroup) Text() string {
+	if g == nil {
+		return ""
+	}
+
+	var lines []string
+
+	for _, comment := range g.List {
+		text := comment.Text
+
+		if strings.HasPrefix(text, "//") {
+			lines = append(lines, strings.TrimPrefix(text[2:], " "))
+
+			continue
+		}
+
+		lines = append(lines, strings.Split(text[2:len(text)-2], "\n")...)
+	}
+
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+
+	for len(lines) != 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+
+	for len(lines) != 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
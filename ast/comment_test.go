@@ -0,0 +1,45 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/durudex/go-polylang/ast"
+)
+
+var CommentGroupTextTests = map[string]struct {
+	comments []string
+	want     string
+}{
+	"Nil":   {},
+	"Line":  {comments: []string{"// first ", "//second"}, want: "first\nsecond\n"},
+	"Block": {comments: []string{"/*\n block\n\n*/"}, want: " block\n"},
+	"Empty": {comments: []string{"//", "/**/"}, want: ""},
+	"Mixed": {comments: []string{"// a", "/* b */"}, want: "a\n b\n"},
+}
+
+func TestCommentGroup_Text(t *testing.T) {
+	for name, test := range CommentGroupTextTests {
+		t.Run(name, func(t *testing.T) {
+			var group *ast.CommentGroup
+
+			if test.comments != nil {
+				group = &ast.CommentGroup{}
+
+				for _, text := range test.comments {
+					group.List = append(group.List, &ast.Comment{Text: text})
+				}
+			}
+
+			if got := group.Text(); got != test.want {
+				t.Fatalf("error: text does not match: %q", got)
+			}
+		})
+	}
+}
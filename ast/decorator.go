@@ -47,6 +47,39 @@ func (d *DecoratorName) Parse(lex *lexer.PeekingLexer) error {
 }
 
 type Decorator struct {
+	Pos    lexer.Position
+	EndPos lexer.Position
+
 	Name      DecoratorName `parser:"'@' @@"`
-	Arguments []string      `parser:"( '(' @Ident ')' )?"`
+	Arguments []FieldPath   `parser:"( '(' @@ ( ',' @@ )* ')' )?"`
+}
+
+// FieldPath is a reference to a field, whose nested names are separated by
+// dots, such as info.owner.
+type FieldPath string
+
+func (f *FieldPath) Parse(lex *lexer.PeekingLexer) error {
+	token := lex.Peek()
+	if !isIdent(token.Value) {
+		return participle.NextMatch
+	}
+	lex.Next()
+
+	path := token.Value
+
+	for lex.Peek().Value == "." {
+		lex.Next()
+
+		token := lex.Peek()
+		if !isIdent(token.Value) {
+			return unexpected(token, "field name")
+		}
+		lex.Next()
+
+		path += "." + token.Value
+	}
+
+	*f = FieldPath(path)
+
+	return nil
 }
@@ -67,7 +67,21 @@ var DecoratorTests = map[string]struct {
 		code: "@call(owner)",
 		want: &ast.Decorator{
 			Name:      ast.Call,
-			Arguments: []string{"owner"},
+			Arguments: []ast.FieldPath{"owner"},
+		},
+	},
+	"Path": {
+		code: "@call(info.owner)",
+		want: &ast.Decorator{
+			Name:      ast.Call,
+			Arguments: []ast.FieldPath{"info.owner"},
+		},
+	},
+	"Arguments": {
+		code: "@delegate(owner, admin)",
+		want: &ast.Decorator{
+			Name:      ast.Delegate,
+			Arguments: []ast.FieldPath{"owner", "admin"},
 		},
 	},
 }
@@ -84,6 +98,8 @@ func TestDecorator(t *testing.T) {
 				t.Fatal("error: parsing polylang code: ", err)
 			}
 
+			zeroPos(got)
+
 			if !reflect.DeepEqual(got, test.want) {
 				t.Fatal("error: decorator does not match")
 			}
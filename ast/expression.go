@@ -0,0 +1,502 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package ast
+
+import (
+	"strings"
+
+	"github.com/alecthomas/participle/v2"
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+// Expression is a node of the expression tree. Exactly one of its fields
+// besides the positions is set.
+type Expression struct {
+	Pos    lexer.Position
+	EndPos lexer.Position
+
+	Unary  *UnaryExpr
+	Update *UpdateExpr
+	Binary *BinaryExpr
+	Call   *CallExpr
+	Member *MemberExpr
+	Index  *IndexExpr
+	Array  *ArrayLit
+	Object *ObjectLit
+	Value  *Value
+}
+
+type UnaryExpr struct {
+	Pos    lexer.Position
+	EndPos lexer.Position
+
+	Operator Operator
+	Operand  *Expression
+}
+
+// UpdateExpr is an increment or decrement such as "i++" or "--i". Its operand
+// is a variable, a member or an element.
+type UpdateExpr struct {
+	Pos    lexer.Position
+	EndPos lexer.Position
+
+	Operator Operator
+	Operand  *Expression
+	Prefix   bool
+}
+
+type BinaryExpr struct {
+	Pos    lexer.Position
+	EndPos lexer.Position
+
+	Left     *Expression
+	Operator Operator
+	Right    *Expression
+}
+
+type CallExpr struct {
+	Pos    lexer.Position
+	EndPos lexer.Position
+
+	Callee    *Expression
+	Arguments []*Expression
+}
+
+// MemberExpr is a property access such as "this.name" or "ctx.publicKey".
+type MemberExpr struct {
+	Pos    lexer.Position
+	EndPos lexer.Position
+
+	Object   *Expression
+	Property string
+}
+
+// IndexExpr is an element access such as "this.items[0]".
+type IndexExpr struct {
+	Pos    lexer.Position
+	EndPos lexer.Position
+
+	Object *Expression
+	Index  *Expression
+}
+
+// ArrayLit is an array literal such as "[a, b]".
+type ArrayLit struct {
+	Pos    lexer.Position
+	EndPos lexer.Position
+
+	Elements []*Expression
+}
+
+// ObjectLit is an object literal such as "{ name: n, count: 0 }".
+type ObjectLit struct {
+	Pos    lexer.Position
+	EndPos lexer.Position
+
+	Properties []*Property
+}
+
+// Property is a property of an object literal. Its key is written as an
+// identifier or a string literal, and Key holds the name that it denotes.
+type Property struct {
+	Pos    lexer.Position
+	EndPos lexer.Position
+
+	Key   string
+	Value *Expression
+
+	// Raw is the key as it is written, which is empty in trees that were
+	// built by hand.
+	Raw string
+}
+
+var (
+	StringToUnaryOperator = map[string]Operator{
+		"!": Not, "~": BitNot, "-": Subtract, "+": Add,
+	}
+	StringToUpdateOperator = map[string]Operator{"++": Increment, "--": Decrement}
+)
+
+// Parse reads an expression using precedence climbing, because the operator
+// table can not be expressed with participle grammar without left recursion.
+func (e *Expression) Parse(lex *lexer.PeekingLexer) error {
+	expr, err := parseBinary(lex, 1)
+	if err != nil {
+		return err
+	}
+
+	*e = *expr
+
+	return nil
+}
+
+func parseBinary(lex *lexer.PeekingLexer, precedence int) (*Expression, error) {
+	left, err := parseUnary(lex)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		next := lex.Clone()
+
+		var op Operator
+		if err := op.Parse(next); err != nil || op.Precedence() < precedence {
+			return left, nil
+		}
+		*lex = *next
+
+		rightPrecedence := op.Precedence() + 1
+		if op.RightAssociative() {
+			rightPrecedence = op.Precedence()
+		}
+
+		right, err := parseOperand(lex, func(lex *lexer.PeekingLexer) (*Expression, error) {
+			return parseBinary(lex, rightPrecedence)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		left = &Expression{
+			Pos:    left.Pos,
+			EndPos: right.EndPos,
+			Binary: &BinaryExpr{
+				Pos:      left.Pos,
+				EndPos:   right.EndPos,
+				Left:     left,
+				Operator: op,
+				Right:    right,
+			},
+		}
+	}
+}
+
+func parseUnary(lex *lexer.PeekingLexer) (*Expression, error) {
+	token := lex.Peek()
+
+	if op, ok := parseUpdateOperator(lex); ok {
+		operand, err := parseOperand(lex, parseUnary)
+		if err != nil {
+			return nil, err
+		}
+
+		return newUpdate(token.Pos, operand.EndPos, op, operand, true)
+	}
+
+	op, ok := StringToUnaryOperator[token.Value]
+	if !ok {
+		return parsePostfix(lex)
+	}
+	lex.Next()
+
+	operand, err := parseOperand(lex, parseUnary)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Expression{
+		Pos:    token.Pos,
+		EndPos: operand.EndPos,
+		Unary: &UnaryExpr{
+			Pos:      token.Pos,
+			EndPos:   operand.EndPos,
+			Operator: op,
+			Operand:  operand,
+		},
+	}, nil
+}
+
+func parsePostfix(lex *lexer.PeekingLexer) (*Expression, error) {
+	expr, err := parsePrimary(lex)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		var node Expression
+
+		if op, ok := parseUpdateOperator(lex); ok {
+			return newUpdate(expr.Pos, lex.RawPeek().Pos, op, expr, false)
+		}
+
+		switch lex.Peek().Value {
+		case "(":
+			lex.Next()
+
+			args, err := parseList(lex, ")", false)
+			if err != nil {
+				return nil, err
+			}
+
+			node.Call = &CallExpr{Callee: expr, Arguments: args}
+		case ".":
+			lex.Next()
+
+			property := lex.Peek()
+			if !isIdent(property.Value) {
+				return nil, unexpected(property, "property name")
+			}
+			lex.Next()
+
+			node.Member = &MemberExpr{Object: expr, Property: property.Value}
+		case "[":
+			lex.Next()
+
+			index, err := parseOperand(lex, func(lex *lexer.PeekingLexer) (*Expression, error) {
+				return parseBinary(lex, 1)
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			if err := expect(lex, "]"); err != nil {
+				return nil, err
+			}
+
+			node.Index = &IndexExpr{Object: expr, Index: index}
+		default:
+			return expr, nil
+		}
+
+		node.Pos, node.EndPos = expr.Pos, lex.RawPeek().Pos
+
+		switch {
+		case node.Call != nil:
+			node.Call.Pos, node.Call.EndPos = node.Pos, node.EndPos
+		case node.Member != nil:
+			node.Member.Pos, node.Member.EndPos = node.Pos, node.EndPos
+		case node.Index != nil:
+			node.Index.Pos, node.Index.EndPos = node.Pos, node.EndPos
+		}
+
+		expr = &node
+	}
+}
+
+// parseUpdateOperator reads "++" or "--" if they are next, written without
+// space between the signs.
+func parseUpdateOperator(lex *lexer.PeekingLexer) (Operator, bool) {
+	next := lex.Clone()
+
+	first := next.Next()
+	if first.EOF() || !adjacent(first, next.Peek()) {
+		return 0, false
+	}
+
+	op, ok := StringToUpdateOperator[first.Value+next.Peek().Value]
+	if !ok {
+		return 0, false
+	}
+	next.Next()
+
+	*lex = *next
+
+	return op, true
+}
+
+func newUpdate(
+	pos, end lexer.Position,
+	op Operator,
+	operand *Expression,
+	prefix bool,
+) (*Expression, error) {
+	if operand.Member == nil && operand.Index == nil &&
+		(operand.Value == nil || operand.Value.Ident == nil) {
+		return nil, participle.Errorf(operand.Pos, "invalid operand of %s", op)
+	}
+
+	return &Expression{
+		Pos:    pos,
+		EndPos: end,
+		Update: &UpdateExpr{
+			Pos:      pos,
+			EndPos:   end,
+			Operator: op,
+			Operand:  operand,
+			Prefix:   prefix,
+		},
+	}, nil
+}
+
+// parsePrimary reads a value or an array or object literal.
+func parsePrimary(lex *lexer.PeekingLexer) (*Expression, error) {
+	token := lex.Peek()
+
+	switch token.Value {
+	case "[":
+		lex.Next()
+
+		elements, err := parseList(lex, "]", true)
+		if err != nil {
+			return nil, err
+		}
+
+		end := lex.RawPeek().Pos
+
+		return &Expression{
+			Pos:    token.Pos,
+			EndPos: end,
+			Array:  &ArrayLit{Pos: token.Pos, EndPos: end, Elements: elements},
+		}, nil
+	case "{":
+		lex.Next()
+
+		properties, err := parseProperties(lex)
+		if err != nil {
+			return nil, err
+		}
+
+		end := lex.RawPeek().Pos
+
+		return &Expression{
+			Pos:    token.Pos,
+			EndPos: end,
+			Object: &ObjectLit{Pos: token.Pos, EndPos: end, Properties: properties},
+		}, nil
+	}
+
+	var value Value
+	if err := value.Parse(lex); err != nil {
+		return nil, err
+	}
+
+	return &Expression{Pos: value.Pos, EndPos: value.EndPos, Value: &value}, nil
+}
+
+// parseProperties reads the comma separated properties of an object literal up
+// to and including the closing brace, the opening brace must already be
+// consumed.
+func parseProperties(lex *lexer.PeekingLexer) ([]*Property, error) {
+	var properties []*Property
+
+	for lex.Peek().Value != "}" {
+		if len(properties) != 0 {
+			if err := expect(lex, ","); err != nil {
+				return nil, err
+			}
+
+			if lex.Peek().Value == "}" {
+				break
+			}
+		}
+
+		key := lex.Peek()
+
+		var name string
+
+		switch {
+		case isIdent(key.Value):
+			name = key.Value
+		case strings.HasPrefix(key.Value, "'") || strings.HasPrefix(key.Value, "\""):
+			value, err := unquote(key.Value, key.Pos)
+			if err != nil {
+				return nil, err
+			}
+
+			name = value
+		default:
+			return nil, unexpected(key, "property name")
+		}
+		lex.Next()
+
+		if err := expect(lex, ":"); err != nil {
+			return nil, err
+		}
+
+		value, err := parseOperand(lex, func(lex *lexer.PeekingLexer) (*Expression, error) {
+			return parseBinary(lex, 1)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		properties = append(properties, &Property{
+			Pos:    key.Pos,
+			EndPos: value.EndPos,
+			Key:    name,
+			Value:  value,
+			Raw:    key.Value,
+		})
+	}
+	lex.Next()
+
+	return properties, nil
+}
+
+// parseList reads comma separated expressions up to and including the closing
+// token, the opening token must already be consumed. A comma after the last
+// expression is accepted if trailing is set.
+func parseList(lex *lexer.PeekingLexer, closing string, trailing bool) ([]*Expression, error) {
+	var list []*Expression
+
+	for lex.Peek().Value != closing {
+		if len(list) != 0 {
+			if err := expect(lex, ","); err != nil {
+				return nil, err
+			}
+
+			if trailing && lex.Peek().Value == closing {
+				break
+			}
+		}
+
+		expr, err := parseOperand(lex, func(lex *lexer.PeekingLexer) (*Expression, error) {
+			return parseBinary(lex, 1)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, expr)
+	}
+	lex.Next()
+
+	return list, nil
+}
+
+// parseOperand parses an expression that is required by the tokens already
+// consumed, so that a missing one is reported instead of backtracking.
+func parseOperand(
+	lex *lexer.PeekingLexer,
+	parse func(*lexer.PeekingLexer) (*Expression, error),
+) (*Expression, error) {
+	expr, err := parse(lex)
+	if err == participle.NextMatch {
+		return nil, unexpected(lex.Peek(), "expression")
+	}
+
+	return expr, err
+}
+
+func isIdent(value string) bool {
+	if value == "" {
+		return false
+	}
+
+	first := value[0]
+
+	return first == '_' || first >= 'a' && first <= 'z' || first >= 'A' && first <= 'Z'
+}
+
+func expect(lex *lexer.PeekingLexer, value string) error {
+	token := lex.Peek()
+	if token.Value != value {
+		return unexpected(token, "\""+value+"\"")
+	}
+	lex.Next()
+
+	return nil
+}
+
+func unexpected(token lexer.Token, expected string) error {
+	if token.EOF() {
+		return participle.Errorf(token.Pos, "unexpected end of input (expected %s)", expected)
+	}
+
+	return participle.Errorf(token.Pos, "unexpected token %q (expected %s)", token.Value, expected)
+}
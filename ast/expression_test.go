@@ -0,0 +1,315 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package ast_test
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/durudex/go-polylang"
+	"github.com/durudex/go-polylang/ast"
+
+	"github.com/alecthomas/participle/v2"
+)
+
+func ident(v string) *ast.Expression {
+	return &ast.Expression{Value: &ast.Value{Ident: &v}}
+}
+
+func number(v int) *ast.Expression {
+	return &ast.Expression{Value: &ast.Value{Number: &ast.NumberLit{Raw: strconv.Itoa(v)}}}
+}
+
+// str returns a single quoted string literal of v, which has no escapes.
+func str(v string) *ast.Expression {
+	return &ast.Expression{Value: &ast.Value{String: &ast.StringLit{Raw: "'" + v + "'", Value: v}}}
+}
+
+func binary(left *ast.Expression, op ast.Operator, right *ast.Expression) *ast.Expression {
+	return &ast.Expression{
+		Binary: &ast.BinaryExpr{Left: left, Operator: op, Right: right},
+	}
+}
+
+func unary(op ast.Operator, operand *ast.Expression) *ast.Expression {
+	return &ast.Expression{
+		Unary: &ast.UnaryExpr{Operator: op, Operand: operand},
+	}
+}
+
+func update(op ast.Operator, operand *ast.Expression, prefix bool) *ast.Expression {
+	return &ast.Expression{
+		Update: &ast.UpdateExpr{Operator: op, Operand: operand, Prefix: prefix},
+	}
+}
+
+func call(callee *ast.Expression, args ...*ast.Expression) *ast.Expression {
+	return &ast.Expression{
+		Call: &ast.CallExpr{Callee: callee, Arguments: args},
+	}
+}
+
+func member(object *ast.Expression, property string) *ast.Expression {
+	return &ast.Expression{
+		Member: &ast.MemberExpr{Object: object, Property: property},
+	}
+}
+
+func index(object, index *ast.Expression) *ast.Expression {
+	return &ast.Expression{
+		Index: &ast.IndexExpr{Object: object, Index: index},
+	}
+}
+
+func array(elements ...*ast.Expression) *ast.Expression {
+	return &ast.Expression{Array: &ast.ArrayLit{Elements: elements}}
+}
+
+func object(properties ...*ast.Property) *ast.Expression {
+	return &ast.Expression{Object: &ast.ObjectLit{Properties: properties}}
+}
+
+func sub(expr *ast.Expression) *ast.Expression {
+	return &ast.Expression{Value: &ast.Value{Sub: expr}}
+}
+
+var ExpressionTests = map[string]struct {
+	code string
+	want *ast.Expression
+}{
+	"OK": {
+		code: "this.id == id",
+		want: binary(member(ident("this"), "id"), ast.Equal, ident("id")),
+	},
+	"Value": {
+		code: "id",
+		want: ident("id"),
+	},
+	"Precedence": {
+		code: "a + b * c",
+		want: binary(
+			ident("a"), ast.Add, binary(ident("b"), ast.Multiply, ident("c")),
+		),
+	},
+	"Left Associative": {
+		code: "a - b - c",
+		want: binary(
+			binary(ident("a"), ast.Subtract, ident("b")), ast.Subtract, ident("c"),
+		),
+	},
+	"Right Associative": {
+		code: "a = b += c",
+		want: binary(
+			ident("a"), ast.Assign, binary(ident("b"), ast.AssignAdd, ident("c")),
+		),
+	},
+	"Exponent": {
+		code: "2 ** 3 ** 2",
+		want: binary(
+			number(2), ast.Exponent, binary(number(3), ast.Exponent, number(2)),
+		),
+	},
+	"Logical": {
+		code: "x == 1 && y == 2 || z",
+		want: binary(
+			binary(
+				binary(ident("x"), ast.Equal, number(1)),
+				ast.And,
+				binary(ident("y"), ast.Equal, number(2)),
+			),
+			ast.Or,
+			ident("z"),
+		),
+	},
+	"Comparison": {
+		code: "a << 1 <= b & c",
+		want: binary(
+			binary(ident("a"), ast.ShiftLeft, number(1)),
+			ast.LessThanOrEqual,
+			binary(ident("b"), ast.BitAnd, ident("c")),
+		),
+	},
+	"Unary": {
+		code: "!a && -b",
+		want: binary(
+			unary(ast.Not, ident("a")), ast.And, unary(ast.Subtract, ident("b")),
+		),
+	},
+	"Unary Plus": {
+		code: "+a - ~b",
+		want: binary(
+			unary(ast.Add, ident("a")), ast.Subtract, unary(ast.BitNot, ident("b")),
+		),
+	},
+	"Double Negation": {
+		code: "- -a",
+		want: unary(ast.Subtract, unary(ast.Subtract, ident("a"))),
+	},
+	"Postfix Update": {
+		code: "i++ < n",
+		want: binary(update(ast.Increment, ident("i"), false), ast.LessThan, ident("n")),
+	},
+	"Prefix Update": {
+		code: "-++this.items[0]",
+		want: unary(ast.Subtract, update(
+			ast.Increment, index(member(ident("this"), "items"), number(0)), true,
+		)),
+	},
+	"Update Operands": {
+		code: "a+++b - --c",
+		want: binary(
+			binary(update(ast.Increment, ident("a"), false), ast.Add, ident("b")),
+			ast.Subtract,
+			update(ast.Decrement, ident("c"), true),
+		),
+	},
+	"Parentheses": {
+		code: "(a + b) * c",
+		want: binary(
+			sub(binary(ident("a"), ast.Add, ident("b"))), ast.Multiply, ident("c"),
+		),
+	},
+	"Call": {
+		code: "error('message', 1 + 2)",
+		want: call(
+			ident("error"), str("message"), binary(number(1), ast.Add, number(2)),
+		),
+	},
+	"Empty Call": {
+		code: "selfdestruct()",
+		want: call(ident("selfdestruct")),
+	},
+	"Member": {
+		code: "this.info.author",
+		want: member(member(ident("this"), "info"), "author"),
+	},
+	"Index": {
+		code: "this.items[i + 1]",
+		want: index(
+			member(ident("this"), "items"), binary(ident("i"), ast.Add, number(1)),
+		),
+	},
+	"Method Call": {
+		code: "arr.push(ctx.publicKey)",
+		want: call(
+			member(ident("arr"), "push"), member(ident("ctx"), "publicKey"),
+		),
+	},
+	"Array": {
+		code: "[a, [], b + 1]",
+		want: array(ident("a"), array(), binary(ident("b"), ast.Add, number(1))),
+	},
+	"Object": {
+		code: "{ name: n, 'full name': { count: 0 }, \"\\u{61}\": [] }",
+		want: object(
+			&ast.Property{Key: "name", Value: ident("n"), Raw: "name"},
+			&ast.Property{Key: "full name", Value: object(
+				&ast.Property{Key: "count", Value: number(0), Raw: "count"},
+			), Raw: "'full name'"},
+			&ast.Property{Key: "a", Value: array(), Raw: `"\u{61}"`},
+		),
+	},
+	"Trailing Commas": {
+		code: "[1, { a: 2, },]",
+		want: array(number(1), object(&ast.Property{Key: "a", Value: number(2), Raw: "a"})),
+	},
+	"Literal Member": {
+		code: "[1, 2].length",
+		want: member(array(number(1), number(2)), "length"),
+	},
+	"Postfix Chain": {
+		code: "-this.items[0].value",
+		want: unary(
+			ast.Subtract,
+			member(index(member(ident("this"), "items"), number(0)), "value"),
+		),
+	},
+}
+
+func TestExpression(t *testing.T) {
+	parser := participle.MustBuild[ast.Expression](
+		participle.Lexer(polylang.Lexer),
+	)
+
+	for name, test := range ExpressionTests {
+		t.Run(name, func(t *testing.T) {
+			got, err := parser.ParseString("", test.code)
+			if err != nil {
+				t.Fatal("error: parsing polylang code: ", err)
+			}
+
+			zeroPos(got)
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatal("error: expression does not match")
+			}
+		})
+	}
+}
+
+func BenchmarkExpression(b *testing.B) {
+	parser := participle.MustBuild[ast.Expression](
+		participle.Lexer(polylang.Lexer),
+	)
+
+	for name, test := range ExpressionTests {
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				parser.ParseString("", test.code) //nolint:errcheck
+			}
+		})
+	}
+}
+
+var ExpressionErrorTests = map[string]string{
+	"Missing Operand":      "a +",
+	"Unclosed Call":        "f(a, b",
+	"Unclosed Parentheses": "(a + b",
+	"Trailing Comma":       "f(a,)",
+	"Update Literal":       "1++",
+	"Update Call":          "--f()",
+	"Spaced Operator":      "a * * b",
+	"Unclosed Array":       "[a, b",
+	"Unclosed Object":      "{ a: 1",
+	"Missing Colon":        "{ a 1 }",
+	"Property Name":        "{ 1: a }",
+	"Only Comma":           "[,]",
+	"Double Comma":         "{ a: 1,, }",
+}
+
+func TestExpression_Error(t *testing.T) {
+	parser := participle.MustBuild[ast.Expression](
+		participle.Lexer(polylang.Lexer),
+	)
+
+	for name, code := range ExpressionErrorTests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := parser.ParseString("", code); err == nil {
+				t.Fatal("error: expected parsing error")
+			}
+		})
+	}
+}
+
+func TestExpression_Position(t *testing.T) {
+	parser := participle.MustBuild[ast.Expression](
+		participle.Lexer(polylang.Lexer),
+	)
+
+	got, err := parser.ParseString("", "a + b * c")
+	if err != nil {
+		t.Fatal("error: parsing polylang code: ", err)
+	}
+
+	right := got.Binary.Right
+
+	if right.Pos.Offset != 4 || right.Binary.Right.Pos.Offset != 8 {
+		t.Fatal("error: expression position does not match")
+	}
+}
@@ -39,6 +39,8 @@ const (
 	AssignSub
 	AssignAdd
 	Assign
+	Increment
+	Decrement
 )
 
 var (
@@ -47,6 +49,7 @@ var (
 		Add: "+", Subtract: "-", ShiftLeft: "<<", ShiftRight: ">>", BitAnd: "&", BitXor: "^",
 		BitOr: "|", LessThan: "<", GreaterThan: ">", LessThanOrEqual: "<=", GreaterThanOrEqual: ">=",
 		Equal: "==", NotEqual: "!=", And: "&&", Or: "||", AssignSub: "-=", AssignAdd: "+=", Assign: "=",
+		Increment: "++", Decrement: "--",
 	}
 	StringToOperator = map[string]Operator{
 		"!": Not, "~": BitNot, "**": Exponent, "*": Multiply, "/": Divide, "%": Modulo,
@@ -56,8 +59,47 @@ var (
 	}
 )
 
+// BinaryPrecedence maps every binary operator to its binding power, from the
+// loosest assignments up to the tightest exponentiation. Operators missing from
+// the map can not be used between two operands.
+var BinaryPrecedence = map[Operator]int{
+	AssignSub: 1, AssignAdd: 1, Assign: 1,
+	Or:    2,
+	And:   3,
+	Equal: 4, NotEqual: 4,
+	LessThan: 5, GreaterThan: 5, LessThanOrEqual: 5, GreaterThanOrEqual: 5,
+	BitOr:     6,
+	BitXor:    7,
+	BitAnd:    8,
+	ShiftLeft: 9, ShiftRight: 9,
+	Add: 10, Subtract: 10,
+	Multiply: 11, Divide: 11, Modulo: 11,
+	Exponent: 12,
+}
+
 func (o Operator) String() string { return OperatorToString[o] }
 
+// Precedence returns the binding power of the binary operator, or zero if the
+// operator is not binary.
+func (o Operator) Precedence() int { return BinaryPrecedence[o] }
+
+// RightAssociative reports whether a chain of the operator groups from the
+// right, as assignments and exponentiation do.
+func (o Operator) RightAssociative() bool {
+	switch o {
+	case Exponent, AssignSub, AssignAdd, Assign:
+		return true
+	default:
+		return false
+	}
+}
+
+// adjacent reports whether the next token directly follows the token, so that
+// they can be read as one operator.
+func adjacent(token, next lexer.Token) bool {
+	return next.Pos.Offset == token.Pos.Offset+len(token.Value)
+}
+
 func (o *Operator) Parse(lex *lexer.PeekingLexer) error {
 	token := lex.Peek()
 
@@ -70,7 +112,7 @@ func (o *Operator) Parse(lex *lexer.PeekingLexer) error {
 		next := lex.Peek()
 
 		two, ok := StringToOperator[token.Value+next.Value]
-		if !ok {
+		if !ok || !adjacent(token, next) {
 			*o = one
 		} else {
 			lex.Next()
@@ -7,57 +7,103 @@
 
 package ast
 
+import "github.com/alecthomas/participle/v2/lexer"
+
 type Statement struct {
+	Pos    lexer.Position
+	EndPos lexer.Position
+
 	Compound *CompoundStatement `parser:"@@"`
 	Simple   *SimpleStatement   `parser:"| @@"`
 }
 
 type CompoundStatement struct {
+	Pos    lexer.Position
+	EndPos lexer.Position
+
 	If    *If    `parser:"@@"`
 	While *While `parser:"| @@"`
 	For   *For   `parser:"| @@"`
+	Block *Block `parser:"| @@"`
+}
+
+// Block is a block statement such as "{ let a = 1; }", whose variables are
+// only visible inside of it.
+type Block struct {
+	Pos    lexer.Position
+	EndPos lexer.Position
+
+	Statements []*Statement `parser:"'{' @@* '}'"`
 }
 
 type SimpleStatement struct {
+	Pos    lexer.Position
+	EndPos lexer.Position
+
 	Small *SmallStatement `parser:"@@ ';'"`
 }
 
 type SmallStatement struct {
+	Pos    lexer.Position
+	EndPos lexer.Position
+
 	Break      bool        `parser:"( @'break'? )!"`
-	Return     *Expression `parser:"| 'return' @@"`
+	Continue   bool        `parser:"| @'continue'"`
+	Return     *Return     `parser:"| @@"`
 	Throw      *Expression `parser:"| 'throw' @@"`
 	Let        *Let        `parser:"| @@"`
 	Expression *Expression `parser:"| @@"`
 }
 
-type StatementsOrSimple struct {
-	Statements []*Statement     `parser:"'{' @@* '}'"`
-	Simple     *SimpleStatement `parser:"| @@"`
+// Return is a return statement. Its value is nil in "return;".
+type Return struct {
+	Pos    lexer.Position
+	EndPos lexer.Position
+
+	Value *Expression `parser:"'return' @@?"`
 }
 
-type Expression struct {
-	Left     *Value   `parser:"@@"`
-	Operator Operator `parser:"( @@ )?"`
-	Right    *Value   `parser:"( @@ )?"`
+// StatementsOrSimple is the body of an if statement or of its else branch: a
+// block, a compound statement such as the if statement of "else if", or a
+// simple statement.
+type StatementsOrSimple struct {
+	Pos    lexer.Position
+	EndPos lexer.Position
+
+	Statements []*Statement       `parser:"'{' @@* '}'"`
+	Compound   *CompoundStatement `parser:"| @@"`
+	Simple     *SimpleStatement   `parser:"| @@"`
 }
 
 type If struct {
+	Pos    lexer.Position
+	EndPos lexer.Position
+
 	Condition *Expression         `parser:"'if' '(' @@ ')'"`
 	Statement *StatementsOrSimple `parser:"( @@ )?"`
 	Else      *StatementsOrSimple `parser:"( 'else' @@ )?"`
 }
 
 type While struct {
+	Pos    lexer.Position
+	EndPos lexer.Position
+
 	Condition  *Expression  `parser:"'while' '(' @@ ')'"`
 	Statements []*Statement `parser:"'{' @@* '}'"`
 }
 
 type Let struct {
+	Pos    lexer.Position
+	EndPos lexer.Position
+
 	Ident      string      `parser:"'let' @Ident '='"`
 	Expression *Expression `parser:"@@"`
 }
 
 type For struct {
+	Pos    lexer.Position
+	EndPos lexer.Position
+
 	Initial    *ForInitial  `parser:"'for' '(' @@ ';'"`
 	Condition  *Expression  `parser:"@@ ';'"`
 	Post       *Expression  `parser:"@@ ')'"`
@@ -65,6 +111,9 @@ type For struct {
 }
 
 type ForInitial struct {
+	Pos    lexer.Position
+	EndPos lexer.Position
+
 	Let        *Let        `parser:"@@"`
 	Expression *Expression `parser:"| @@"`
 }
@@ -25,43 +25,26 @@ var SmallStatementTests = map[string]struct {
 		code: "break",
 		want: &ast.SmallStatement{Break: true},
 	},
+	"Continue": {
+		code: "continue",
+		want: &ast.SmallStatement{Continue: true},
+	},
 	"Return": {
 		code: "return this.name == name",
 		want: &ast.SmallStatement{
-			Return: &ast.Expression{
-				Left: &ast.Value{
-					Ident: func(v string) *string {
-						return &v
-					}("this.name"),
-				},
-				Operator: ast.Equal,
-				Right: &ast.Value{
-					Ident: func(v string) *string {
-						return &v
-					}("name"),
-				},
+			Return: &ast.Return{
+				Value: binary(member(ident("this"), "name"), ast.Equal, ident("name")),
 			},
 		},
 	},
+	"Bare Return": {
+		code: "return",
+		want: &ast.SmallStatement{Return: &ast.Return{}},
+	},
 	"Throw": {
 		code: "throw error('error message')",
 		want: &ast.SmallStatement{
-			Throw: &ast.Expression{
-				Left: &ast.Value{
-					Ident: func(v string) *string {
-						return &v
-					}("error"),
-				},
-				Right: &ast.Value{
-					Sub: &ast.Expression{
-						Left: &ast.Value{
-							String: func(v string) *string {
-								return &v
-							}("'error message'"),
-						},
-					},
-				},
-			},
+			Throw: call(ident("error"), str("error message")),
 		},
 	},
 }
@@ -78,6 +61,8 @@ func TestSmallStatement(t *testing.T) {
 				t.Fatal("error: parsing polylang code: ", err)
 			}
 
+			zeroPos(got)
+
 			if !reflect.DeepEqual(got, test.want) {
 				t.Fatal("error: small statement does not match")
 			}
@@ -99,61 +84,6 @@ func BenchmarkSmallStatement(b *testing.B) {
 	}
 }
 
-var ExpressionTests = map[string]struct {
-	code string
-	want *ast.Expression
-}{
-	"OK": {
-		code: "this.id == id",
-		want: &ast.Expression{
-			Left: &ast.Value{
-				Ident: func(v string) *string {
-					return &v
-				}("this.id"),
-			},
-			Operator: ast.Equal,
-			Right: &ast.Value{
-				Ident: func(v string) *string {
-					return &v
-				}("id"),
-			},
-		},
-	},
-}
-
-func TestExpression(t *testing.T) {
-	parser := participle.MustBuild[ast.Expression](
-		participle.Lexer(polylang.Lexer),
-	)
-
-	for name, test := range ExpressionTests {
-		t.Run(name, func(t *testing.T) {
-			got, err := parser.ParseString("", test.code)
-			if err != nil {
-				t.Fatal("error: parsing polylang code: ", err)
-			}
-
-			if !reflect.DeepEqual(got, test.want) {
-				t.Fatal("error: expression does not match")
-			}
-		})
-	}
-}
-
-func BenchmarkExpression(b *testing.B) {
-	parser := participle.MustBuild[ast.Expression](
-		participle.Lexer(polylang.Lexer),
-	)
-
-	for name, test := range ExpressionTests {
-		b.Run(name, func(b *testing.B) {
-			for i := 0; i < b.N; i++ {
-				parser.ParseString("", test.code) //nolint:errcheck
-			}
-		})
-	}
-}
-
 var IfTests = map[string]struct {
 	code string
 	want *ast.If
@@ -161,37 +91,15 @@ var IfTests = map[string]struct {
 	"OK": {
 		code: "if (this.id != id) { this.name = name; }",
 		want: &ast.If{
-			Condition: &ast.Expression{
-				Left: &ast.Value{
-					Ident: func(v string) *string {
-						return &v
-					}("this.id"),
-				},
-				Operator: ast.NotEqual,
-				Right: &ast.Value{
-					Ident: func(v string) *string {
-						return &v
-					}("id"),
-				},
-			},
+			Condition: binary(member(ident("this"), "id"), ast.NotEqual, ident("id")),
 			Statement: &ast.StatementsOrSimple{
 				Statements: []*ast.Statement{
 					{
 						Simple: &ast.SimpleStatement{
 							Small: &ast.SmallStatement{
-								Expression: &ast.Expression{
-									Left: &ast.Value{
-										Ident: func(v string) *string {
-											return &v
-										}("this.name"),
-									},
-									Operator: ast.Assign,
-									Right: &ast.Value{
-										Ident: func(v string) *string {
-											return &v
-										}("name"),
-									},
-								},
+								Expression: binary(
+									member(ident("this"), "name"), ast.Assign, ident("name"),
+								),
 							},
 						},
 					},
@@ -202,32 +110,16 @@ var IfTests = map[string]struct {
 	"Else": {
 		code: "if (this.name) {} else { this.age = age; }",
 		want: &ast.If{
-			Condition: &ast.Expression{
-				Left: &ast.Value{
-					Ident: func(v string) *string {
-						return &v
-					}("this.name"),
-				},
-			},
+			Condition: member(ident("this"), "name"),
 			Statement: &ast.StatementsOrSimple{},
 			Else: &ast.StatementsOrSimple{
 				Statements: []*ast.Statement{
 					{
 						Simple: &ast.SimpleStatement{
 							Small: &ast.SmallStatement{
-								Expression: &ast.Expression{
-									Left: &ast.Value{
-										Ident: func(v string) *string {
-											return &v
-										}("this.age"),
-									},
-									Operator: ast.Assign,
-									Right: &ast.Value{
-										Ident: func(v string) *string {
-											return &v
-										}("age"),
-									},
-								},
+								Expression: binary(
+									member(ident("this"), "age"), ast.Assign, ident("age"),
+								),
 							},
 						},
 					},
@@ -238,19 +130,31 @@ var IfTests = map[string]struct {
 	"Simple": {
 		code: "if (name) return 123;",
 		want: &ast.If{
-			Condition: &ast.Expression{
-				Left: &ast.Value{
-					Ident: func(v string) *string { return &v }("name"),
+			Condition: ident("name"),
+			Statement: &ast.StatementsOrSimple{
+				Simple: &ast.SimpleStatement{
+					Small: &ast.SmallStatement{Return: &ast.Return{Value: number(123)}},
 				},
 			},
+		},
+	},
+	"Else If": {
+		code: "if (a) return; else if (b) {} else continue;",
+		want: &ast.If{
+			Condition: ident("a"),
 			Statement: &ast.StatementsOrSimple{
 				Simple: &ast.SimpleStatement{
-					Small: &ast.SmallStatement{
-						Return: &ast.Expression{
-							Left: &ast.Value{
-								Number: func(v int) *int {
-									return &v
-								}(123),
+					Small: &ast.SmallStatement{Return: &ast.Return{}},
+				},
+			},
+			Else: &ast.StatementsOrSimple{
+				Compound: &ast.CompoundStatement{
+					If: &ast.If{
+						Condition: ident("b"),
+						Statement: &ast.StatementsOrSimple{},
+						Else: &ast.StatementsOrSimple{
+							Simple: &ast.SimpleStatement{
+								Small: &ast.SmallStatement{Continue: true},
 							},
 						},
 					},
@@ -258,6 +162,17 @@ var IfTests = map[string]struct {
 			},
 		},
 	},
+	"Compound": {
+		code: "if (a) while (b) {}",
+		want: &ast.If{
+			Condition: ident("a"),
+			Statement: &ast.StatementsOrSimple{
+				Compound: &ast.CompoundStatement{
+					While: &ast.While{Condition: ident("b")},
+				},
+			},
+		},
+	},
 }
 
 func TestIf(t *testing.T) {
@@ -272,6 +187,8 @@ func TestIf(t *testing.T) {
 				t.Fatal("error: parsing polylang code: ", err)
 			}
 
+			zeroPos(got)
+
 			if !reflect.DeepEqual(got, test.want) {
 				t.Fatal("error: if statement does not match")
 			}
@@ -300,19 +217,9 @@ var WhileTests = map[string]struct {
 	"OK": {
 		code: "while (this.balance < balance) { break; }",
 		want: &ast.While{
-			Condition: &ast.Expression{
-				Left: &ast.Value{
-					Ident: func(v string) *string {
-						return &v
-					}("this.balance"),
-				},
-				Operator: ast.LessThan,
-				Right: &ast.Value{
-					Ident: func(v string) *string {
-						return &v
-					}("balance"),
-				},
-			},
+			Condition: binary(
+				member(ident("this"), "balance"), ast.LessThan, ident("balance"),
+			),
 			Statements: []*ast.Statement{
 				{
 					Simple: &ast.SimpleStatement{
@@ -338,6 +245,8 @@ func TestWhile(t *testing.T) {
 				t.Fatal("error: parsing polylang code: ", err)
 			}
 
+			zeroPos(got)
+
 			if !reflect.DeepEqual(got, test.want) {
 				t.Fatal("error: while statement does not match")
 			}
@@ -366,11 +275,19 @@ var LetTests = map[string]struct {
 	"OK": {
 		code: "let i = 10",
 		want: &ast.Let{
-			Ident: "i",
-			Expression: &ast.Expression{
-				Left: &ast.Value{
-					Number: func(v int) *int { return &v }(10),
-				}},
+			Ident:      "i",
+			Expression: number(10),
+		},
+	},
+	"Expression": {
+		code: "let total = this.balance - amount * 2",
+		want: &ast.Let{
+			Ident: "total",
+			Expression: binary(
+				member(ident("this"), "balance"),
+				ast.Subtract,
+				binary(ident("amount"), ast.Multiply, number(2)),
+			),
 		},
 	},
 }
@@ -387,6 +304,8 @@ func TestLet(t *testing.T) {
 				t.Fatal("error: parsing polylang code: ", err)
 			}
 
+			zeroPos(got)
+
 			if !reflect.DeepEqual(got, test.want) {
 				t.Fatal("error: let statement does not match")
 			}
@@ -417,42 +336,12 @@ var ForTests = map[string]struct {
 		want: &ast.For{
 			Initial: &ast.ForInitial{
 				Let: &ast.Let{
-					Ident: "i",
-					Expression: &ast.Expression{
-						Left: &ast.Value{
-							Number: func(v int) *int {
-								return &v
-							}(0),
-						},
-					},
-				},
-			},
-			Condition: &ast.Expression{
-				Left: &ast.Value{
-					Ident: func(v string) *string {
-						return &v
-					}("i"),
-				},
-				Operator: ast.LessThan,
-				Right: &ast.Value{
-					Number: func(v int) *int {
-						return &v
-					}(100),
-				},
-			},
-			Post: &ast.Expression{
-				Left: &ast.Value{
-					Ident: func(v string) *string {
-						return &v
-					}("i"),
-				},
-				Operator: ast.Add,
-				Right: &ast.Value{
-					Number: func(v int) *int {
-						return &v
-					}(1),
+					Ident:      "i",
+					Expression: number(0),
 				},
 			},
+			Condition: binary(ident("i"), ast.LessThan, number(100)),
+			Post:      binary(ident("i"), ast.Add, number(1)),
 			Statements: []*ast.Statement{
 				{
 					Simple: &ast.SimpleStatement{
@@ -478,6 +367,8 @@ func TestFor(t *testing.T) {
 				t.Fatal("error: parsing polylang code: ", err)
 			}
 
+			zeroPos(got)
+
 			if !reflect.DeepEqual(got, test.want) {
 				t.Fatal("error: for statement does not match")
 			}
@@ -35,6 +35,9 @@ var (
 )
 
 type Type struct {
+	Pos    lexer.Position
+	EndPos lexer.Position
+
 	Basic   BasicType `parser:"@@"`
 	Array   bool      `parser:"@( '[' ']' )?"`
 	Map     *Map      `parser:"| @@"`
@@ -43,10 +46,19 @@ type Type struct {
 }
 
 type Map struct {
+	Pos    lexer.Position
+	EndPos lexer.Position
+
 	Key   BasicType `parser:"'map' '<' @@ ','"`
 	Value Type      `parser:"@@ '>'"`
 }
 
+// IsZero reports whether no type is set, as in the return type of a function
+// that does not declare one.
+func (t *Type) IsZero() bool {
+	return t.Basic == 0 && t.Map == nil && t.Object == nil && t.Foreign == ""
+}
+
 func (t BasicType) String() string { return TypeToString[t] }
 
 func (t *BasicType) Parse(lex *lexer.PeekingLexer) error {
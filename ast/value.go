@@ -7,10 +7,279 @@
 
 package ast
 
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/alecthomas/participle/v2"
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
 type Value struct {
-	Number  *int        `parser:"@Number"`
-	String  *string     `parser:"| @String"`
-	Boolean bool        `parser:"| ( @'true' | 'false' )"`
-	Ident   *string     `parser:"| @Ident"`
-	Sub     *Expression `parser:"| '(' ( @@ )? ')'"`
+	Pos    lexer.Position
+	EndPos lexer.Position
+
+	Number  *NumberLit
+	String  *StringLit
+	Boolean *bool
+	Ident   *string
+	Sub     *Expression
+}
+
+func (v *Value) Parse(lex *lexer.PeekingLexer) error {
+	token := lex.Peek()
+	if token.EOF() {
+		return participle.NextMatch
+	}
+
+	*v = Value{Pos: token.Pos}
+
+	switch first := token.Value[0]; {
+	case token.Value == "true" || token.Value == "false":
+		b := token.Value == "true"
+		v.Boolean = &b
+	case token.Value == "(":
+		lex.Next()
+
+		sub, err := parseOperand(lex, func(lex *lexer.PeekingLexer) (*Expression, error) {
+			return parseBinary(lex, 1)
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := expect(lex, ")"); err != nil {
+			return err
+		}
+
+		v.Sub = sub
+		v.EndPos = lex.RawPeek().Pos
+
+		return nil
+	case first == '\'' || first == '"':
+		value, err := unquote(token.Value, token.Pos)
+		if err != nil {
+			return err
+		}
+
+		v.String = &StringLit{Raw: token.Value, Value: value}
+	case first >= '0' && first <= '9' || first == '.' && len(token.Value) > 1:
+		n := &NumberLit{Raw: token.Value}
+		if _, err := n.BigFloat(); err != nil {
+			return participle.Errorf(token.Pos, "invalid number %q", token.Value)
+		}
+
+		v.Number = n
+	case isIdent(token.Value):
+		v.Ident = &token.Value
+	default:
+		return participle.NextMatch
+	}
+	lex.Next()
+
+	v.EndPos = lex.RawPeek().Pos
+
+	return nil
+}
+
+// NumberLit is a number literal as it is written, such as 1_000, 0x1F, .5 or
+// 1.5e-3. Polylang numbers are double precision floats, as in JavaScript, and
+// the accessors convert the literal to the Go types that can hold it.
+type NumberLit struct {
+	Raw string
+}
+
+// digits returns the literal without underscores, which may only separate
+// two digits. The error is set if the literal is malformed.
+func (n NumberLit) digits() (string, error) {
+	digit := func(b byte) bool { return b >= '0' && b <= '9' }
+
+	prefix, body := "", n.Raw
+	if n.hex() {
+		prefix, body = n.Raw[:2], n.Raw[2:]
+		digit = func(b byte) bool {
+			return b >= '0' && b <= '9' || b >= 'a' && b <= 'f' || b >= 'A' && b <= 'F'
+		}
+	}
+
+	var b strings.Builder
+
+	b.WriteString(prefix)
+
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+
+		switch {
+		case c == '_':
+			if i == 0 || i == len(body)-1 || !digit(body[i-1]) || !digit(body[i+1]) {
+				return "", fmt.Errorf("invalid number %q", n.Raw)
+			}
+		case digit(c) || prefix == "" && strings.IndexByte(".eE+-", c) >= 0:
+			b.WriteByte(c)
+		default:
+			return "", fmt.Errorf("invalid number %q", n.Raw)
+		}
+	}
+
+	if b.Len() == len(prefix) {
+		return "", fmt.Errorf("invalid number %q", n.Raw)
+	}
+
+	return b.String(), nil
+}
+
+func (n NumberLit) hex() bool {
+	return len(n.Raw) > 1 && n.Raw[0] == '0' && (n.Raw[1] == 'x' || n.Raw[1] == 'X')
+}
+
+// Float64 returns the float64 nearest to the literal, which is the value of the
+// number. The error is set if the literal is malformed or out of range.
+func (n NumberLit) Float64() (float64, error) {
+	digits, err := n.digits()
+	if err != nil {
+		return 0, err
+	}
+
+	if !n.hex() {
+		f, err := strconv.ParseFloat(digits, 64)
+		if errors.Is(err, strconv.ErrRange) {
+			return f, fmt.Errorf("number %q out of range", n.Raw)
+		} else if err != nil {
+			return 0, fmt.Errorf("invalid number %q", n.Raw)
+		}
+
+		return f, nil
+	}
+
+	i, ok := new(big.Int).SetString(digits[2:], 16)
+	if !ok {
+		return 0, fmt.Errorf("invalid number %q", n.Raw)
+	}
+
+	f, _ := new(big.Float).SetInt(i).Float64()
+	if math.IsInf(f, 0) {
+		return f, fmt.Errorf("number %q out of range", n.Raw)
+	}
+
+	return f, nil
+}
+
+// Int64 returns the literal as an integer, such as 1000 for 1e3. The error is
+// set if the literal is malformed, not an integer or does not fit an int64.
+func (n NumberLit) Int64() (int64, error) {
+	f, err := n.BigFloat()
+	if err != nil {
+		return 0, err
+	}
+
+	if f.IsInf() {
+		return 0, fmt.Errorf("number %q out of range", n.Raw)
+	}
+
+	if !f.IsInt() {
+		return 0, fmt.Errorf("number %q is not an integer", n.Raw)
+	}
+
+	i, accuracy := f.Int64()
+	if accuracy != big.Exact {
+		return 0, fmt.Errorf("number %q out of range", n.Raw)
+	}
+
+	return i, nil
+}
+
+// BigFloat returns the literal as a big.Float, whose precision is large enough
+// to hold all of its digits. The error is set if the literal is malformed or
+// its exponent is out of the range of a big.Float.
+func (n NumberLit) BigFloat() (*big.Float, error) {
+	digits, err := n.digits()
+	if err != nil {
+		return nil, err
+	}
+
+	f, _, err := big.ParseFloat(digits, 0, uint(len(digits))*4+64, big.ToNearestEven)
+	if err != nil {
+		if _, ferr := strconv.ParseFloat(digits, 64); errors.Is(ferr, strconv.ErrRange) {
+			return nil, fmt.Errorf("number %q out of range", n.Raw)
+		}
+
+		return nil, fmt.Errorf("invalid number %q", n.Raw)
+	}
+
+	return f, nil
+}
+
+// StringLit is a string literal as it is written, with its quotes and escape
+// sequences, and the string that it denotes.
+type StringLit struct {
+	Raw   string
+	Value string
+}
+
+// escapes maps the characters that follow a backslash to the characters that
+// they stand for.
+var escapes = map[byte]byte{
+	'n': '\n', 't': '\t', 'r': '\r', 'b': '\b', 'f': '\f', 'v': '\v', '0': 0,
+	'\\': '\\', '\'': '\'', '"': '"',
+}
+
+// unquote decodes a quoted string literal that starts at pos. Literals are a
+// single line, so the column of an error is the column of the literal plus
+// the byte offset in it.
+func unquote(raw string, pos lexer.Position) (string, error) {
+	errorf := func(i int, format string, args ...any) error {
+		pos.Offset += i
+		pos.Column += i
+
+		return participle.Errorf(pos, format, args...)
+	}
+
+	quote := raw[0]
+
+	var b strings.Builder
+
+	for i := 1; i < len(raw); {
+		switch c := raw[i]; {
+		case c == quote:
+			return b.String(), nil
+		case c != '\\':
+			b.WriteByte(c)
+			i++
+
+			continue
+		case i+1 == len(raw):
+			return "", errorf(0, "string literal not terminated")
+		}
+
+		if c, ok := escapes[raw[i+1]]; ok {
+			b.WriteByte(c)
+			i += 2
+
+			continue
+		}
+
+		if raw[i+1] != 'u' {
+			return "", errorf(i, "unknown escape sequence %s", raw[i:i+2])
+		}
+
+		end := strings.IndexByte(raw[i:], '}')
+		if !strings.HasPrefix(raw[i+2:], "{") || end < 0 {
+			return "", errorf(i, "invalid escape sequence, expected \\u{...}")
+		}
+
+		r, err := strconv.ParseUint(raw[i+3:i+end], 16, 32)
+		if err != nil || !utf8.ValidRune(rune(r)) {
+			return "", errorf(i, "invalid Unicode code point %s", raw[i:i+end+1])
+		}
+
+		b.WriteRune(rune(r))
+		i += end + 1
+	}
+
+	return "", errorf(0, "string literal not terminated")
 }
@@ -9,6 +9,7 @@ package ast_test
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/durudex/go-polylang"
@@ -23,29 +24,47 @@ var ValueTests = map[string]struct {
 }{
 	"Number": {
 		code: "10",
-		want: &ast.Value{
-			Number: func(v int) *int { return &v }(10),
-		},
+		want: &ast.Value{Number: &ast.NumberLit{Raw: "10"}},
+	},
+	"Decimal Number": {
+		code: "1.25",
+		want: &ast.Value{Number: &ast.NumberLit{Raw: "1.25"}},
+	},
+	"Fraction Number": {
+		code: ".5",
+		want: &ast.Value{Number: &ast.NumberLit{Raw: ".5"}},
+	},
+	"Hex Number": {
+		code: "0x1F",
+		want: &ast.Value{Number: &ast.NumberLit{Raw: "0x1F"}},
+	},
+	"Exponent Number": {
+		code: "1_000.5e-3",
+		want: &ast.Value{Number: &ast.NumberLit{Raw: "1_000.5e-3"}},
 	},
 	"Single Quoted String": {
 		code: "'Durudex'",
-		want: &ast.Value{
-			String: func(v string) *string { return &v }("'Durudex'"),
-		},
+		want: &ast.Value{String: &ast.StringLit{Raw: "'Durudex'", Value: "Durudex"}},
 	},
 	"Double Quoted String": {
 		code: "\"Durudex\"",
-		want: &ast.Value{
-			String: func(v string) *string { return &v }("\"Durudex\""),
-		},
+		want: &ast.Value{String: &ast.StringLit{Raw: "\"Durudex\"", Value: "Durudex"}},
+	},
+	"Escaped String": {
+		code: `'it\'s\t"a\\b"\n'`,
+		want: &ast.Value{String: &ast.StringLit{Raw: `'it\'s\t"a\\b"\n'`, Value: "it's\t\"a\\b\"\n"}},
+	},
+	"Unicode String": {
+		code: `"\u{48}\u{1F600}"`,
+		want: &ast.Value{String: &ast.StringLit{Raw: `"\u{48}\u{1F600}"`, Value: "H\U0001F600"}},
 	},
 	"True": {
 		code: "true",
-		want: &ast.Value{Boolean: true},
+		want: &ast.Value{Boolean: func(v bool) *bool { return &v }(true)},
 	},
 	"False": {
 		code: "false",
-		want: &ast.Value{Boolean: false},
+		want: &ast.Value{Boolean: func(v bool) *bool { return &v }(false)},
 	},
 	"Ident": {
 		code: "Durudex",
@@ -56,15 +75,7 @@ var ValueTests = map[string]struct {
 	"Sub Expression": {
 		code: "(this.id == id)",
 		want: &ast.Value{
-			Sub: &ast.Expression{
-				Left: &ast.Value{
-					Ident: func(v string) *string { return &v }("this.id"),
-				},
-				Operator: ast.Equal,
-				Right: &ast.Value{
-					Ident: func(v string) *string { return &v }("id"),
-				},
-			},
+			Sub: binary(member(ident("this"), "id"), ast.Equal, ident("id")),
 		},
 	},
 }
@@ -81,6 +92,8 @@ func TestValue(t *testing.T) {
 				t.Fatal("error: parsing polylang code: ", err)
 			}
 
+			zeroPos(got)
+
 			if !reflect.DeepEqual(got, test.want) {
 				t.Fatal("error: value does not match")
 			}
@@ -88,6 +101,91 @@ func TestValue(t *testing.T) {
 	}
 }
 
+func TestNumberLit(t *testing.T) {
+	tests := map[string]struct {
+		raw     string
+		float   float64
+		integer int64
+		err     bool
+	}{
+		"Integer":      {raw: "1_000", float: 1000, integer: 1000},
+		"Hex":          {raw: "0x1F", float: 31, integer: 31},
+		"Exponent":     {raw: "1e3", float: 1000, integer: 1000},
+		"Fraction":     {raw: ".5", float: 0.5, err: true},
+		"Negative":     {raw: "1.5e-3", float: 0.0015, err: true},
+		"Out of Range": {raw: "9223372036854775808", float: 9223372036854775808, err: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			n := ast.NumberLit{Raw: test.raw}
+
+			f, err := n.Float64()
+			if err != nil {
+				t.Fatal("error: converting to float64: ", err)
+			}
+
+			if f != test.float {
+				t.Fatal("error: unexpected float64: ", f)
+			}
+
+			i, err := n.Int64()
+			if (err != nil) != test.err {
+				t.Fatal("error: unexpected int64 error: ", err)
+			}
+
+			if i != test.integer {
+				t.Fatal("error: unexpected int64: ", i)
+			}
+
+			b, err := n.BigFloat()
+			if err != nil {
+				t.Fatal("error: converting to big.Float: ", err)
+			}
+
+			if bf, _ := b.Float64(); bf != test.float {
+				t.Fatal("error: unexpected big.Float: ", b)
+			}
+		})
+	}
+}
+
+func TestNumberLit_Invalid(t *testing.T) {
+	for _, raw := range []string{"1e", "1__0", "1_", "_1", "1_.5", "1e_3", "0x", "0x_1", "0x1G", "inf"} {
+		t.Run(raw, func(t *testing.T) {
+			n := ast.NumberLit{Raw: raw}
+
+			if _, err := n.Float64(); err == nil {
+				t.Fatal("error: expected float64 error")
+			}
+
+			if _, err := n.Int64(); err == nil {
+				t.Fatal("error: expected int64 error")
+			}
+
+			if _, err := n.BigFloat(); err == nil {
+				t.Fatal("error: expected big.Float error")
+			}
+		})
+	}
+}
+
+func TestNumberLit_Range(t *testing.T) {
+	for _, raw := range []string{"1e999999999", "1e9999999999"} {
+		t.Run(raw, func(t *testing.T) {
+			n := ast.NumberLit{Raw: raw}
+
+			if _, err := n.Float64(); err == nil || !strings.Contains(err.Error(), "out of range") {
+				t.Fatal("error: expected float64 range error: ", err)
+			}
+
+			if _, err := n.Int64(); err == nil || !strings.Contains(err.Error(), "out of range") {
+				t.Fatal("error: expected int64 range error: ", err)
+			}
+		})
+	}
+}
+
 func BenchmarkValue(b *testing.B) {
 	parser := participle.MustBuild[ast.Value](
 		participle.Lexer(polylang.Lexer),
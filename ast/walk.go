@@ -0,0 +1,298 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package ast
+
+// Visitor's Visit method is invoked for each node encountered by Walk. If the
+// result visitor w is not nil, Walk visits each of the children of node with
+// the visitor w, followed by a call of w.Visit(nil).
+type Visitor interface {
+	Visit(node any) (w Visitor)
+}
+
+// Walk traverses an AST in depth-first order. It starts by calling
+// v.Visit(node), which must be a pointer to an AST node. Nil children and the
+// missing return type of a function are not visited, and neither are the
+// comments of a program, which are visited through the nodes they document.
+func Walk(v Visitor, node any) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	switch n := node.(type) {
+	case *Program:
+		walkList(v, n.Nodes)
+	case *Node:
+		if n.Collection != nil {
+			Walk(v, n.Collection)
+		}
+
+		if n.Function != nil {
+			Walk(v, n.Function)
+		}
+	case *Collection:
+		if n.Doc != nil {
+			Walk(v, n.Doc)
+		}
+
+		walkList(v, n.Decorators)
+		walkList(v, n.Items)
+	case *Item:
+		walkList(v, n.Decorators)
+
+		if n.Function != nil {
+			Walk(v, n.Function)
+		}
+
+		if n.Field != nil {
+			Walk(v, n.Field)
+		}
+
+		if n.Index != nil {
+			Walk(v, n.Index)
+		}
+	case *Field:
+		if n.Doc != nil {
+			Walk(v, n.Doc)
+		}
+
+		Walk(v, &n.Type)
+
+		if n.Comment != nil {
+			Walk(v, n.Comment)
+		}
+	case *Index:
+		if n.Doc != nil {
+			Walk(v, n.Doc)
+		}
+
+		walkList(v, n.Fields)
+
+		if n.Comment != nil {
+			Walk(v, n.Comment)
+		}
+	case *Function:
+		if n.Doc != nil {
+			Walk(v, n.Doc)
+		}
+
+		walkList(v, n.Parameters)
+
+		if !n.ReturnType.IsZero() {
+			Walk(v, &n.ReturnType)
+		}
+
+		walkList(v, n.Statements)
+	case *CommentGroup:
+		walkList(v, n.List)
+	case *Type:
+		if n.Map != nil {
+			Walk(v, n.Map)
+		}
+
+		walkList(v, n.Object)
+	case *Map:
+		Walk(v, &n.Value)
+	case *Statement:
+		if n.Compound != nil {
+			Walk(v, n.Compound)
+		}
+
+		if n.Simple != nil {
+			Walk(v, n.Simple)
+		}
+	case *CompoundStatement:
+		if n.If != nil {
+			Walk(v, n.If)
+		}
+
+		if n.While != nil {
+			Walk(v, n.While)
+		}
+
+		if n.For != nil {
+			Walk(v, n.For)
+		}
+
+		if n.Block != nil {
+			Walk(v, n.Block)
+		}
+	case *Block:
+		walkList(v, n.Statements)
+	case *SimpleStatement:
+		if n.Small != nil {
+			Walk(v, n.Small)
+		}
+	case *SmallStatement:
+		if n.Return != nil {
+			Walk(v, n.Return)
+		}
+
+		if n.Throw != nil {
+			Walk(v, n.Throw)
+		}
+
+		if n.Let != nil {
+			Walk(v, n.Let)
+		}
+
+		if n.Expression != nil {
+			Walk(v, n.Expression)
+		}
+	case *Return:
+		if n.Value != nil {
+			Walk(v, n.Value)
+		}
+	case *StatementsOrSimple:
+		walkList(v, n.Statements)
+
+		if n.Compound != nil {
+			Walk(v, n.Compound)
+		}
+
+		if n.Simple != nil {
+			Walk(v, n.Simple)
+		}
+	case *If:
+		if n.Condition != nil {
+			Walk(v, n.Condition)
+		}
+
+		if n.Statement != nil {
+			Walk(v, n.Statement)
+		}
+
+		if n.Else != nil {
+			Walk(v, n.Else)
+		}
+	case *While:
+		if n.Condition != nil {
+			Walk(v, n.Condition)
+		}
+
+		walkList(v, n.Statements)
+	case *Let:
+		if n.Expression != nil {
+			Walk(v, n.Expression)
+		}
+	case *For:
+		if n.Initial != nil {
+			Walk(v, n.Initial)
+		}
+
+		if n.Condition != nil {
+			Walk(v, n.Condition)
+		}
+
+		if n.Post != nil {
+			Walk(v, n.Post)
+		}
+
+		walkList(v, n.Statements)
+	case *ForInitial:
+		if n.Let != nil {
+			Walk(v, n.Let)
+		}
+
+		if n.Expression != nil {
+			Walk(v, n.Expression)
+		}
+	case *Expression:
+		switch {
+		case n.Unary != nil:
+			Walk(v, n.Unary)
+		case n.Update != nil:
+			Walk(v, n.Update)
+		case n.Binary != nil:
+			Walk(v, n.Binary)
+		case n.Call != nil:
+			Walk(v, n.Call)
+		case n.Member != nil:
+			Walk(v, n.Member)
+		case n.Index != nil:
+			Walk(v, n.Index)
+		case n.Array != nil:
+			Walk(v, n.Array)
+		case n.Object != nil:
+			Walk(v, n.Object)
+		case n.Value != nil:
+			Walk(v, n.Value)
+		}
+	case *UnaryExpr:
+		if n.Operand != nil {
+			Walk(v, n.Operand)
+		}
+	case *UpdateExpr:
+		if n.Operand != nil {
+			Walk(v, n.Operand)
+		}
+	case *BinaryExpr:
+		if n.Left != nil {
+			Walk(v, n.Left)
+		}
+
+		if n.Right != nil {
+			Walk(v, n.Right)
+		}
+	case *CallExpr:
+		if n.Callee != nil {
+			Walk(v, n.Callee)
+		}
+
+		walkList(v, n.Arguments)
+	case *MemberExpr:
+		if n.Object != nil {
+			Walk(v, n.Object)
+		}
+	case *IndexExpr:
+		if n.Object != nil {
+			Walk(v, n.Object)
+		}
+
+		if n.Index != nil {
+			Walk(v, n.Index)
+		}
+	case *ArrayLit:
+		walkList(v, n.Elements)
+	case *ObjectLit:
+		walkList(v, n.Properties)
+	case *Property:
+		if n.Value != nil {
+			Walk(v, n.Value)
+		}
+	case *Value:
+		if n.Sub != nil {
+			Walk(v, n.Sub)
+		}
+	}
+
+	v.Visit(nil)
+}
+
+func walkList[T any](v Visitor, list []*T) {
+	for _, node := range list {
+		Walk(v, node)
+	}
+}
+
+type inspector func(any) bool
+
+func (f inspector) Visit(node any) Visitor {
+	if f(node) {
+		return f
+	}
+
+	return nil
+}
+
+// Inspect traverses an AST in depth-first order: It starts by calling
+// f(node); node must not be nil. If f returns true, Inspect invokes f
+// recursively for each of the children of node, followed by a call of
+// f(nil).
+func Inspect(node any, f func(any) bool) {
+	Walk(inspector(f), node)
+}
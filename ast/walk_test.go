@@ -0,0 +1,162 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package ast_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/durudex/go-polylang"
+	"github.com/durudex/go-polylang/ast"
+	"github.com/durudex/go-polylang/parser"
+
+	"github.com/alecthomas/participle/v2"
+)
+
+// walkCode uses every node type.
+const walkCode = `
+// A doc.
+@public
+collection A {
+    id: string; // line
+    info: { balances: map<string, number>; };
+
+    @index([id, desc]);
+
+    @call(id)
+    function f(a: number): string {
+        let b = -a + g(a)[0].c + [a, { c: 1 }].length;
+        if (b) { b = 1; } else b = 2;
+        while (b) { if (a) continue; else if (b) { break; } }
+        { return; }
+        for (let i = 0; i < 1; i++) { throw error('x'); }
+        return (b);
+    }
+}`
+
+func parseProgram(t *testing.T, code string) *ast.Program {
+	parser := participle.MustBuild[ast.Program](
+		participle.Lexer(polylang.Lexer),
+	)
+
+	prog, err := parser.ParseString("", code)
+	if err != nil {
+		t.Fatal("error: parsing polylang code: ", err)
+	}
+
+	return prog
+}
+
+func TestInspect_Types(t *testing.T) {
+	want := []any{
+		&ast.Program{}, &ast.Node{}, &ast.Collection{}, &ast.Item{}, &ast.Field{},
+		&ast.Index{}, &ast.IndexField{}, &ast.Function{}, &ast.Decorator{}, &ast.Type{},
+		&ast.Map{}, &ast.Statement{}, &ast.CompoundStatement{}, &ast.SimpleStatement{},
+		&ast.SmallStatement{}, &ast.StatementsOrSimple{}, &ast.If{}, &ast.While{},
+		&ast.Let{}, &ast.For{}, &ast.ForInitial{}, &ast.Expression{}, &ast.UnaryExpr{},
+		&ast.BinaryExpr{}, &ast.CallExpr{}, &ast.MemberExpr{}, &ast.IndexExpr{}, &ast.Value{},
+		&ast.Block{}, &ast.Return{}, &ast.UpdateExpr{}, &ast.ArrayLit{}, &ast.ObjectLit{}, &ast.Property{},
+		&ast.CommentGroup{}, &ast.Comment{},
+	}
+
+	prog, err := parser.ParseFile("", []byte(walkCode), parser.ParseComments)
+	if err != nil {
+		t.Fatal("error: parsing polylang code: ", err)
+	}
+
+	visited := map[reflect.Type]bool{}
+
+	ast.Inspect(prog, func(node any) bool {
+		visited[reflect.TypeOf(node)] = true
+
+		return true
+	})
+
+	for _, node := range want {
+		if !visited[reflect.TypeOf(node)] {
+			t.Errorf("error: %T is not visited", node)
+		}
+	}
+}
+
+type visitor struct {
+	depth int
+	out   *strings.Builder
+}
+
+func (v visitor) Visit(node any) ast.Visitor {
+	if node == nil {
+		fmt.Fprintf(v.out, "%d:end ", v.depth)
+
+		return nil
+	}
+
+	fmt.Fprintf(v.out, "%d:%T ", v.depth, node)
+
+	if _, ok := node.(*ast.Field); ok {
+		return nil
+	}
+
+	return visitor{depth: v.depth + 1, out: v.out}
+}
+
+func TestInspect_Nil(t *testing.T) {
+	value := &ast.Expression{Value: &ast.Value{}}
+
+	for name, node := range map[string]any{
+		"For":    &ast.For{},
+		"If":     &ast.If{},
+		"While":  &ast.While{},
+		"Let":    &ast.Let{},
+		"Binary": &ast.BinaryExpr{Left: value},
+		"Unary":  &ast.UnaryExpr{},
+		"Member": &ast.MemberExpr{},
+		"Index":  &ast.IndexExpr{Index: value},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ast.Inspect(node, func(node any) bool {
+				if node != nil && reflect.ValueOf(node).IsNil() {
+					t.Fatalf("error: nil %T is visited", node)
+				}
+
+				return true
+			})
+		})
+	}
+}
+
+func TestWalk(t *testing.T) {
+	var out strings.Builder
+
+	ast.Walk(visitor{out: &out}, parseProgram(t, "collection A { id: string; }"))
+
+	want := "0:*ast.Program 1:*ast.Node 2:*ast.Collection 3:*ast.Item 4:*ast.Field 4:end 3:end 2:end 1:end "
+	if out.String() != want {
+		t.Fatalf("error: walk order does not match: %s", out.String())
+	}
+}
+
+func TestInspect_Stop(t *testing.T) {
+	var values int
+
+	ast.Inspect(parseProgram(t, walkCode), func(node any) bool {
+		if _, ok := node.(*ast.Value); ok {
+			values++
+		}
+
+		_, ok := node.(*ast.Function)
+
+		return !ok
+	})
+
+	if values != 0 {
+		t.Fatalf("error: inspected %d values inside function", values)
+	}
+}
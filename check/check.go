@@ -0,0 +1,134 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Package check reports programs that are parsed successfully but have no
+// meaning, such as collections with duplicate fields or indexes on fields
+// that do not exist.
+package check
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/durudex/go-polylang/ast"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+type Code int
+
+const (
+	DuplicateCollection Code = iota + 1
+	DuplicateField
+	DuplicateFunction
+	DuplicateParameter
+	UnknownCollection
+	UnknownIndexField
+	MissingID
+	InvalidID
+	TypeMismatch
+	InvalidOperand
+	InvalidCondition
+	InvalidReturn
+)
+
+var CodeToString = map[Code]string{
+	DuplicateCollection: "duplicate-collection",
+	DuplicateField:      "duplicate-field",
+	DuplicateFunction:   "duplicate-function",
+	DuplicateParameter:  "duplicate-parameter",
+	UnknownCollection:   "unknown-collection",
+	UnknownIndexField:   "unknown-index-field",
+	MissingID:           "missing-id",
+	InvalidID:           "invalid-id",
+	TypeMismatch:        "type-mismatch",
+	InvalidOperand:      "invalid-operand",
+	InvalidCondition:    "invalid-condition",
+	InvalidReturn:       "invalid-return",
+}
+
+func (c Code) String() string { return CodeToString[c] }
+
+// Diagnostic is a single problem found in a program.
+type Diagnostic struct {
+	Pos     lexer.Position
+	Code    Code
+	Message string
+}
+
+func (d *Diagnostic) Error() string {
+	return fmt.Sprintf("%s: %s (%s)", d.Pos, d.Message, d.Code)
+}
+
+// Diagnostics is the list of problems found in a program, in source order.
+type Diagnostics []*Diagnostic
+
+func (d Diagnostics) Error() string {
+	lines := make([]string, len(d))
+
+	for i, diagnostic := range d {
+		lines[i] = diagnostic.Error()
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Err returns the diagnostics as an error, or nil when there are none.
+func (d Diagnostics) Err() error {
+	if len(d) == 0 {
+		return nil
+	}
+
+	return d
+}
+
+type checker struct {
+	collections map[string]*ast.Collection
+	diagnostics Diagnostics
+}
+
+// Check returns the problems found in the program. A program without problems
+// has no diagnostics.
+func Check(prog *ast.Program) Diagnostics {
+	c := &checker{collections: map[string]*ast.Collection{}}
+
+	for _, node := range prog.Nodes {
+		if node.Collection == nil {
+			continue
+		}
+
+		coll := node.Collection
+
+		if prev, ok := c.collections[coll.Name]; ok {
+			c.errorf(coll.Pos, DuplicateCollection,
+				"collection %s is already declared at %s", coll.Name, prev.Pos)
+
+			continue
+		}
+
+		c.collections[coll.Name] = coll
+	}
+
+	for _, node := range prog.Nodes {
+		switch {
+		case node.Collection != nil:
+			c.collection(node.Collection)
+		case node.Function != nil:
+			c.function(node.Function, nil)
+		}
+	}
+
+	return c.diagnostics
+}
+
+func (c *checker) errorf(pos lexer.Position, code Code, format string, args ...any) {
+	c.diagnostics = append(c.diagnostics, &Diagnostic{
+		Pos:     pos,
+		Code:    code,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
@@ -0,0 +1,169 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package check_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/durudex/go-polylang/check"
+	"github.com/durudex/go-polylang/parser"
+)
+
+var CheckTests = map[string]struct {
+	code string
+	want []check.Code
+}{
+	"OK": {
+		code: "collection A { id: string; b: B; info: { name: string; }; @index(info.name, [id, desc]); function f(a: number): map<string, B> {} } " +
+			"collection B { id: string; } function g(b?: B) {}",
+	},
+	"DuplicateCollection": {
+		code: "collection A { id: string; } collection A { id: string; }",
+		want: []check.Code{check.DuplicateCollection},
+	},
+	"DuplicateField": {
+		code: "collection A { id: string; a: string; a: number; b: { c: string; c: string; }; }",
+		want: []check.Code{check.DuplicateField, check.DuplicateField},
+	},
+	"DuplicateFunction": {
+		code: "collection A { id: string; function f() {} function f() {} }",
+		want: []check.Code{check.DuplicateFunction},
+	},
+	"DuplicateParameter": {
+		code: "collection A { id: string; function f(a: string, a: number) {} } function g(b: string, b: string) {}",
+		want: []check.Code{check.DuplicateParameter, check.DuplicateParameter},
+	},
+	"UnknownCollection": {
+		code: "collection A { id: string; b: B; c: map<string, C>; d: { e: E; }; function f(g: G): H {} }",
+		want: []check.Code{
+			check.UnknownCollection, check.UnknownCollection, check.UnknownCollection,
+			check.UnknownCollection, check.UnknownCollection,
+		},
+	},
+	"UnknownIndexField": {
+		code: "collection A { id: string; info: { name: string; }; @index(name, info.age, id.length); }",
+		want: []check.Code{check.UnknownIndexField, check.UnknownIndexField, check.UnknownIndexField},
+	},
+	"MissingID": {
+		code: "collection A { name: string; }",
+		want: []check.Code{check.MissingID},
+	},
+	"InvalidID": {
+		code: "collection A { id?: string; } collection B { id: number; } collection C { id: string[]; }",
+		want: []check.Code{check.InvalidID, check.InvalidID, check.InvalidID},
+	},
+	"Body": {
+		code: "collection A { id: string; age?: number; tags: string[]; info: { name: string; }; " +
+			"function f(name: string, ages: map<string, number>): boolean { " +
+			"let n = this.tags.length + 1; this.age = n * 2; this.info.name = name + '!'; this.age = ages[name]; " +
+			"for (let i = 0; i < n; i++) { this.tags[i] = name; } " +
+			"if (this.age) { while (!false && n != 0) { n -= 1; } } return n > 0 || ctx.publicKey == name; } }",
+	},
+	"TypeMismatch": {
+		code: "collection A { id: string; age: number; info: { name: string; }; " +
+			"function f(name: string) { this.age = name; let n = 1; n = 'x'; this.info = name; this.info.name = 1; if (n == name) {} } }",
+		want: []check.Code{
+			check.TypeMismatch, check.TypeMismatch, check.TypeMismatch,
+			check.TypeMismatch, check.TypeMismatch,
+		},
+	},
+	"InvalidOperand": {
+		code: "function f(a: string, b: boolean) { let c = a - 1; let d = !a; let e = b && 1; let f = a + true; let g = -b; a++; let h = +a; }",
+		want: []check.Code{
+			check.InvalidOperand, check.InvalidOperand, check.InvalidOperand,
+			check.InvalidOperand, check.InvalidOperand, check.InvalidOperand,
+			check.InvalidOperand,
+		},
+	},
+	"InvalidCondition": {
+		code: "function f(a: number, b?: string) { if (a) {} while ('x') {} for (let i = 0; i; i += 1) {} if (b) {} }",
+		want: []check.Code{check.InvalidCondition, check.InvalidCondition, check.InvalidCondition},
+	},
+	"OptionalOperand": {
+		code: "collection A { id: string; age?: number; function f(x: boolean, y?: string) { " +
+			"if (!this.age) {} let a = this.age && x; let b = x || y; let c = !y && !this.age; } }",
+	},
+	"InvalidReturn": {
+		code: "function f() { return 1; return; } function g(): number { if (true) return 'x'; else if (false) return; return 1; }",
+		want: []check.Code{check.InvalidReturn, check.InvalidReturn, check.InvalidReturn},
+	},
+	"Literals": {
+		code: "collection A { id: string; tags: string[]; ages: number[]; info: { name: string; }; " +
+			"function f(name: string) { this.tags = [name, 'x']; this.tags = []; this.info = { name: name }; " +
+			"this.ages = [1, name]; this.ages = ['x']; let n = [{ a: 1 - 'x' }]; } }",
+		want: []check.Code{check.TypeMismatch, check.InvalidOperand},
+	},
+	"Scope": {
+		code: "function f() { if (true) { let a = 'x'; } { let a = 'x'; } let a = 1; a = 2; }",
+	},
+}
+
+func TestCheck(t *testing.T) {
+	for name, test := range CheckTests {
+		t.Run(name, func(t *testing.T) {
+			prog, err := parser.Must.ParseString("", test.code)
+			if err != nil {
+				t.Fatal("error: parsing polylang code: ", err)
+			}
+
+			var got []check.Code
+			for _, diagnostic := range check.Check(prog) {
+				got = append(got, diagnostic.Code)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("error: diagnostics do not match: %v", check.Check(prog))
+			}
+		})
+	}
+}
+
+func TestCheck_Position(t *testing.T) {
+	prog, err := parser.Must.ParseString("contract.polylang", "collection A {\n    id: string;\n    b: B;\n}")
+	if err != nil {
+		t.Fatal("error: parsing polylang code: ", err)
+	}
+
+	diagnostics := check.Check(prog)
+	if len(diagnostics) != 1 {
+		t.Fatalf("error: expected one diagnostic: %v", diagnostics)
+	}
+
+	want := "contract.polylang:3:8: collection B is not declared (unknown-collection)"
+	if got := diagnostics.Error(); got != want {
+		t.Fatalf("error: diagnostic does not match: %s", got)
+	}
+
+	if diagnostics.Err() == nil {
+		t.Fatal("error: expected error")
+	}
+}
+
+func TestCheck_Message(t *testing.T) {
+	prog, err := parser.Must.ParseString("", "collection A { id: string; age: number; function f(a: string[]) { this.age = a; } }")
+	if err != nil {
+		t.Fatal("error: parsing polylang code: ", err)
+	}
+
+	diagnostics := check.Check(prog)
+	if len(diagnostics) != 1 {
+		t.Fatalf("error: expected one diagnostic: %v", diagnostics)
+	}
+
+	want := "cannot assign string[] to number"
+	if got := diagnostics[0].Message; got != want {
+		t.Fatalf("error: message does not match: %s", got)
+	}
+}
+
+func TestDiagnostics_Err(t *testing.T) {
+	if err := check.Diagnostics(nil).Err(); err != nil {
+		t.Fatal("error: unexpected error: ", err)
+	}
+}
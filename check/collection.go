@@ -0,0 +1,95 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package check
+
+import (
+	"strings"
+
+	"github.com/durudex/go-polylang/ast"
+)
+
+func (c *checker) collection(coll *ast.Collection) {
+	var fields []*ast.Field
+
+	for _, item := range coll.Items {
+		if item.Field != nil {
+			fields = append(fields, item.Field)
+		}
+	}
+
+	c.fields(fields, DuplicateField)
+	c.id(coll, fields)
+
+	this := &ast.Type{Object: fields}
+	functions := map[string]*ast.Function{}
+
+	for _, item := range coll.Items {
+		switch {
+		case item.Function != nil:
+			fn := item.Function
+
+			if prev, ok := functions[fn.Name]; ok {
+				c.errorf(fn.Pos, DuplicateFunction,
+					"function %s is already declared at %s", fn.Name, prev.Pos)
+			} else {
+				functions[fn.Name] = fn
+			}
+
+			c.function(fn, this)
+		case item.Index != nil:
+			for _, field := range item.Index.Fields {
+				if ast.LookupField(fields, strings.Split(string(field.Name), ".")) == nil {
+					c.errorf(field.Pos, UnknownIndexField,
+						"index field %s is not declared in collection %s", field.Name, coll.Name)
+				}
+			}
+		}
+	}
+}
+
+// fields reports fields sharing a name with the code and checks their types.
+func (c *checker) fields(fields []*ast.Field, code Code) {
+	declared := map[string]*ast.Field{}
+
+	for _, field := range fields {
+		if prev, ok := declared[field.Name]; ok {
+			c.errorf(field.Pos, code, "%s is already declared at %s", field.Name, prev.Pos)
+		} else {
+			declared[field.Name] = field
+		}
+
+		c.typ(&field.Type)
+	}
+}
+
+// id reports collections without the required string id field.
+func (c *checker) id(coll *ast.Collection, fields []*ast.Field) {
+	id := ast.LookupField(fields, []string{"id"})
+	if id == nil {
+		c.errorf(coll.Pos, MissingID, "collection %s has no id field", coll.Name)
+
+		return
+	}
+
+	if id.Optional || id.Type.Basic != ast.String || id.Type.Array {
+		c.errorf(id.Pos, InvalidID, "id field of collection %s must be a required string", coll.Name)
+	}
+}
+
+func (c *checker) typ(t *ast.Type) {
+	switch {
+	case t.Map != nil:
+		c.typ(&t.Map.Value)
+	case t.Object != nil:
+		c.fields(t.Object, DuplicateField)
+	case t.Foreign != "":
+		if _, ok := c.collections[t.Foreign]; !ok {
+			c.errorf(t.Pos, UnknownCollection, "collection %s is not declared", t.Foreign)
+		}
+	}
+}
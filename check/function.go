@@ -0,0 +1,407 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package check
+
+import (
+	"github.com/durudex/go-polylang/ast"
+	"github.com/durudex/go-polylang/printer"
+)
+
+var (
+	numberType  = &ast.Type{Basic: ast.Number}
+	stringType  = &ast.Type{Basic: ast.String}
+	booleanType = &ast.Type{Basic: ast.Boolean}
+)
+
+// typed is the type inferred for an expression. Expressions whose type can not
+// be known, such as calls or the context, have no type and are never reported.
+type typed struct {
+	typ      *ast.Type
+	optional bool
+}
+
+// scope holds the variables visible in a block of a function.
+type scope struct {
+	parent *scope
+	vars   map[string]typed
+}
+
+func (s *scope) lookup(name string) (typed, bool) {
+	for ; s != nil; s = s.parent {
+		if v, ok := s.vars[name]; ok {
+			return v, true
+		}
+	}
+
+	return typed{}, false
+}
+
+// body checks the statements of a single function.
+type body struct {
+	*checker
+
+	fn    *ast.Function
+	scope *scope
+}
+
+// function checks the signature and the statements of a function. Functions
+// of a collection see its fields through this.
+func (c *checker) function(fn *ast.Function, this *ast.Type) {
+	c.fields(fn.Parameters, DuplicateParameter)
+
+	if !fn.ReturnType.IsZero() {
+		c.typ(&fn.ReturnType)
+	}
+
+	b := &body{checker: c, fn: fn}
+	b.push()
+
+	if this != nil {
+		b.scope.vars["this"] = typed{typ: this}
+	}
+
+	for _, param := range fn.Parameters {
+		b.scope.vars[param.Name] = typed{typ: &param.Type, optional: param.Optional}
+	}
+
+	b.statements(fn.Statements)
+}
+
+func (b *body) push() {
+	b.scope = &scope{parent: b.scope, vars: map[string]typed{}}
+}
+
+func (b *body) pop() { b.scope = b.scope.parent }
+
+func (b *body) statements(statements []*ast.Statement) {
+	b.push()
+	defer b.pop()
+
+	for _, stmt := range statements {
+		b.statement(stmt)
+	}
+}
+
+func (b *body) statement(stmt *ast.Statement) {
+	switch {
+	case stmt.Compound != nil:
+		b.compound(stmt.Compound)
+	case stmt.Simple != nil:
+		b.small(stmt.Simple.Small)
+	}
+}
+
+func (b *body) small(stmt *ast.SmallStatement) {
+	switch {
+	case stmt.Return != nil:
+		b.ret(stmt.Return)
+	case stmt.Throw != nil:
+		b.expression(stmt.Throw)
+	case stmt.Let != nil:
+		b.let(stmt.Let)
+	case stmt.Expression != nil:
+		b.expression(stmt.Expression)
+	}
+}
+
+func (b *body) ret(stmt *ast.Return) {
+	if stmt.Value == nil {
+		if !b.fn.ReturnType.IsZero() {
+			b.errorf(stmt.Pos, InvalidReturn, "function %s must return %s",
+				b.fn.Name, printer.TypeString(&b.fn.ReturnType))
+		}
+
+		return
+	}
+
+	expr := stmt.Value
+	got := b.expression(expr)
+
+	if b.fn.ReturnType.IsZero() {
+		b.errorf(expr.Pos, InvalidReturn, "function %s does not return a value", b.fn.Name)
+
+		return
+	}
+
+	if got.typ != nil && !assignable(&b.fn.ReturnType, got.typ) {
+		b.errorf(expr.Pos, InvalidReturn, "cannot return %s from function %s returning %s",
+			printer.TypeString(got.typ), b.fn.Name, printer.TypeString(&b.fn.ReturnType))
+	}
+}
+
+func (b *body) let(let *ast.Let) {
+	b.scope.vars[let.Ident] = b.expression(let.Expression)
+}
+
+func (b *body) compound(stmt *ast.CompoundStatement) {
+	switch {
+	case stmt.If != nil:
+		b.condition(stmt.If.Condition)
+		b.statementsOrSimple(stmt.If.Statement)
+		b.statementsOrSimple(stmt.If.Else)
+	case stmt.While != nil:
+		b.condition(stmt.While.Condition)
+		b.statements(stmt.While.Statements)
+	case stmt.For != nil:
+		b.push()
+		defer b.pop()
+
+		switch initial := stmt.For.Initial; {
+		case initial.Let != nil:
+			b.let(initial.Let)
+		case initial.Expression != nil:
+			b.expression(initial.Expression)
+		}
+
+		b.condition(stmt.For.Condition)
+		b.expression(stmt.For.Post)
+		b.statements(stmt.For.Statements)
+	case stmt.Block != nil:
+		b.statements(stmt.Block.Statements)
+	}
+}
+
+func (b *body) statementsOrSimple(stmt *ast.StatementsOrSimple) {
+	switch {
+	case stmt == nil:
+	case stmt.Compound != nil:
+		b.compound(stmt.Compound)
+	case stmt.Simple != nil:
+		b.push()
+		b.small(stmt.Simple.Small)
+		b.pop()
+	default:
+		b.statements(stmt.Statements)
+	}
+}
+
+// condition reports conditions that are not boolean. Optional values are
+// allowed, as testing them checks whether they are set.
+func (b *body) condition(expr *ast.Expression) {
+	got := b.expression(expr)
+
+	if got.typ != nil && !got.optional && !assignable(booleanType, got.typ) {
+		b.errorf(expr.Pos, InvalidCondition, "condition must be boolean, not %s", printer.TypeString(got.typ))
+	}
+}
+
+func (b *body) expression(expr *ast.Expression) typed {
+	switch {
+	case expr.Unary != nil:
+		return b.unary(expr.Unary)
+	case expr.Update != nil:
+		b.operand(expr.Update.Operator, expr.Update.Operand, b.expression(expr.Update.Operand), numberType)
+
+		return typed{typ: numberType}
+	case expr.Binary != nil:
+		return b.binary(expr.Binary)
+	case expr.Call != nil:
+		b.expression(expr.Call.Callee)
+
+		for _, arg := range expr.Call.Arguments {
+			b.expression(arg)
+		}
+
+		return typed{}
+	case expr.Member != nil:
+		return member(b.expression(expr.Member.Object), expr.Member.Property)
+	case expr.Index != nil:
+		object := b.expression(expr.Index.Object)
+		b.expression(expr.Index.Index)
+
+		return index(object)
+	case expr.Array != nil:
+		return b.array(expr.Array)
+	case expr.Object != nil:
+		for _, property := range expr.Object.Properties {
+			b.expression(property.Value)
+		}
+
+		return typed{}
+	case expr.Value != nil:
+		return b.value(expr.Value)
+	default:
+		return typed{}
+	}
+}
+
+// array returns the type of an array literal, which is only known if all of
+// its elements are of the same basic type.
+func (b *body) array(array *ast.ArrayLit) typed {
+	var basic ast.BasicType
+
+	for i, element := range array.Elements {
+		got := b.expression(element)
+
+		switch {
+		case got.typ == nil || got.optional || got.typ.Basic == 0 || got.typ.Array:
+			basic = 0
+		case i == 0:
+			basic = got.typ.Basic
+		case got.typ.Basic != basic:
+			basic = 0
+		}
+	}
+
+	if basic == 0 {
+		return typed{}
+	}
+
+	return typed{typ: &ast.Type{Basic: basic, Array: true}}
+}
+
+func (b *body) value(value *ast.Value) typed {
+	switch {
+	case value.Number != nil:
+		return typed{typ: numberType}
+	case value.String != nil:
+		return typed{typ: stringType}
+	case value.Ident != nil:
+		v, _ := b.scope.lookup(*value.Ident)
+
+		return v
+	case value.Sub != nil:
+		return b.expression(value.Sub)
+	case value.Boolean != nil:
+		return typed{typ: booleanType}
+	default:
+		return typed{}
+	}
+}
+
+func member(object typed, property string) typed {
+	switch t := object.typ; {
+	case t == nil:
+	case t.Object != nil:
+		if field := ast.LookupField(t.Object, []string{property}); field != nil {
+			return typed{typ: &field.Type, optional: field.Optional}
+		}
+	case property == "length" && (t.Array || t.Basic == ast.String):
+		return typed{typ: numberType}
+	}
+
+	return typed{}
+}
+
+func index(object typed) typed {
+	switch t := object.typ; {
+	case t == nil:
+	case t.Array:
+		return typed{typ: &ast.Type{Basic: t.Basic}}
+	case t.Map != nil:
+		return typed{typ: &t.Map.Value, optional: true}
+	}
+
+	return typed{}
+}
+
+func (b *body) unary(expr *ast.UnaryExpr) typed {
+	operand := b.expression(expr.Operand)
+
+	if expr.Operator == ast.Not {
+		b.truth(expr.Operator, expr.Operand, operand)
+
+		return typed{typ: booleanType}
+	}
+
+	b.operand(expr.Operator, expr.Operand, operand, numberType)
+
+	return typed{typ: numberType}
+}
+
+func (b *body) binary(expr *ast.BinaryExpr) typed {
+	left, right := b.expression(expr.Left), b.expression(expr.Right)
+
+	switch expr.Operator {
+	case ast.Assign:
+		if left.typ != nil && right.typ != nil && !assignable(left.typ, right.typ) {
+			b.errorf(expr.Right.Pos, TypeMismatch, "cannot assign %s to %s",
+				printer.TypeString(right.typ), printer.TypeString(left.typ))
+		}
+
+		return left
+	case ast.AssignAdd, ast.Add:
+		if isString(left.typ) || isString(right.typ) {
+			b.operand(expr.Operator, expr.Left, left, stringType)
+			b.operand(expr.Operator, expr.Right, right, stringType)
+
+			return typed{typ: stringType}
+		}
+	case ast.Or, ast.And:
+		b.truth(expr.Operator, expr.Left, left)
+		b.truth(expr.Operator, expr.Right, right)
+
+		return typed{typ: booleanType}
+	case ast.Equal, ast.NotEqual:
+		if left.typ != nil && right.typ != nil &&
+			!assignable(left.typ, right.typ) && !assignable(right.typ, left.typ) {
+			b.errorf(expr.Pos, TypeMismatch, "cannot compare %s with %s",
+				printer.TypeString(left.typ), printer.TypeString(right.typ))
+		}
+
+		return typed{typ: booleanType}
+	case ast.LessThan, ast.GreaterThan, ast.LessThanOrEqual, ast.GreaterThanOrEqual:
+		b.operand(expr.Operator, expr.Left, left, numberType)
+		b.operand(expr.Operator, expr.Right, right, numberType)
+
+		return typed{typ: booleanType}
+	}
+
+	b.operand(expr.Operator, expr.Left, left, numberType)
+	b.operand(expr.Operator, expr.Right, right, numberType)
+
+	return typed{typ: numberType}
+}
+
+// operand reports an operand of the operator that is not of the wanted type.
+func (b *body) operand(op ast.Operator, expr *ast.Expression, got typed, want *ast.Type) {
+	if got.typ != nil && !assignable(want, got.typ) {
+		b.errorf(expr.Pos, InvalidOperand, "operator %s needs %s, not %s",
+			op, printer.TypeString(want), printer.TypeString(got.typ))
+	}
+}
+
+// truth reports operands of logical operators that are not boolean. As in
+// conditions, optional values are allowed.
+func (b *body) truth(op ast.Operator, expr *ast.Expression, got typed) {
+	if !got.optional {
+		b.operand(op, expr, got, booleanType)
+	}
+}
+
+func isString(t *ast.Type) bool {
+	return t != nil && !t.Array && t.Basic == ast.String
+}
+
+// assignable reports whether a value of type got can be stored where a value
+// of type want is expected. Records are assignable to any foreign record.
+func assignable(want, got *ast.Type) bool {
+	switch {
+	case want.Foreign != "":
+		return want.Foreign == got.Foreign || (got.Basic == ast.Record && !got.Array)
+	case want.Map != nil:
+		return got.Map != nil && want.Map.Key == got.Map.Key &&
+			assignable(&want.Map.Value, &got.Map.Value)
+	case want.Object != nil:
+		if got.Object == nil || len(want.Object) != len(got.Object) {
+			return false
+		}
+
+		for _, field := range want.Object {
+			other := ast.LookupField(got.Object, []string{field.Name})
+			if other == nil || (!field.Optional && other.Optional) ||
+				!assignable(&field.Type, &other.Type) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return want.Basic == got.Basic && want.Array == got.Array
+	}
+}
@@ -0,0 +1,43 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Polylang-gen-go generates Go types for the records of Polylang collections.
+//
+// Usage:
+//
+//	polylang-gen-go [flags] path
+//
+// The path is a Polylang file, a directory of Polylang files or a JSON file
+// of collection metadata. The flags are:
+//
+//	-o file
+//		Write the generated code to file instead of standard output.
+//	-p name
+//		Name of the generated package, collections by default.
+package main
+
+import (
+	"flag"
+	"io"
+
+	"github.com/durudex/go-polylang/gogen"
+	"github.com/durudex/go-polylang/internal/gencmd"
+)
+
+func main() {
+	pkg := flag.String("p", gogen.DefaultPackage, "name of the generated package")
+
+	gencmd.Main("polylang-gen-go", "code", generator(pkg))
+}
+
+// generator generates the code of a package, whose name is read when the
+// code is generated.
+func generator(pkg *string) gencmd.Generator {
+	return func(w io.Writer, node any) error {
+		return (&gogen.Config{Package: *pkg}).Fprint(w, node)
+	}
+}
@@ -0,0 +1,30 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/durudex/go-polylang/internal/gencmd"
+)
+
+func TestGenerator(t *testing.T) {
+	pkg := "models"
+
+	var out, errOut bytes.Buffer
+
+	if code := gencmd.Run(generator(&pkg), "../../compiler/fixtures/users.polylang", "", &out, &errOut); code != 0 {
+		t.Fatal("error: unexpected exit code: ", errOut.String())
+	}
+
+	if !strings.Contains(out.String(), "package models\n") || !strings.Contains(out.String(), "type Users struct {") {
+		t.Fatal("error: output does not match: ", out.String())
+	}
+}
@@ -0,0 +1,29 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Polylang-gen-ts generates TypeScript type definitions for Polylang
+// collections.
+//
+// Usage:
+//
+//	polylang-gen-ts [flags] path
+//
+// The path is a Polylang file, a directory of Polylang files or a JSON file
+// of collection metadata. The flags are:
+//
+//	-o file
+//		Write the definitions to file instead of standard output.
+package main
+
+import (
+	"github.com/durudex/go-polylang/internal/gencmd"
+	"github.com/durudex/go-polylang/tsgen"
+)
+
+func main() {
+	gencmd.Main("polylang-gen-ts", "definitions", tsgen.Fprint)
+}
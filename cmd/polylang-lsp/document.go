@@ -0,0 +1,246 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package main
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/durudex/go-polylang/ast"
+	"github.com/durudex/go-polylang/check"
+	"github.com/durudex/go-polylang/parser"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+// document is an open text document with the program parsed from it.
+type document struct {
+	uri  string
+	text string
+	// lines are the offsets at which the lines of the text start.
+	lines []int
+
+	prog   *ast.Program
+	errors parser.ErrorList
+}
+
+func newDocument(uri, text string) *document {
+	d := &document{uri: uri, text: text, lines: []int{0}}
+
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\n' {
+			d.lines = append(d.lines, i+1)
+		}
+	}
+
+	prog, err := parser.ParseFile(uri, []byte(text), parser.AllErrors|parser.ParseComments)
+	if !errors.As(err, &d.errors) && err != nil {
+		d.errors = parser.ErrorList{{Message: err.Error()}}
+	}
+
+	if d.prog = prog; d.prog == nil {
+		d.prog = &ast.Program{}
+	}
+
+	return d
+}
+
+// diagnostics returns the syntax errors of the document or, when there are
+// none, the problems found by the checker.
+func (d *document) diagnostics() []Diagnostic {
+	out := []Diagnostic{}
+
+	for _, e := range d.errors {
+		end := e.Pos.Offset
+		if e.Unexpected != "" && e.Unexpected != "<EOF>" {
+			end += len(e.Unexpected)
+		}
+
+		out = append(out, Diagnostic{
+			Range:    Range{Start: d.position(e.Pos.Offset), End: d.position(end)},
+			Severity: SeverityError,
+			Source:   "polylang",
+			Message:  e.Message,
+		})
+	}
+
+	if len(out) != 0 {
+		return out
+	}
+
+	for _, diagnostic := range check.Check(d.prog) {
+		out = append(out, Diagnostic{
+			Range:    d.word(diagnostic.Pos.Offset),
+			Severity: SeverityError,
+			Code:     diagnostic.Code.String(),
+			Source:   "polylang",
+			Message:  diagnostic.Message,
+		})
+	}
+
+	return out
+}
+
+// position converts a byte offset into an LSP position, whose character is
+// counted in UTF-16 code units.
+func (d *document) position(offset int) Position {
+	offset = clamp(offset, len(d.text))
+	line := sort.Search(len(d.lines), func(i int) bool { return d.lines[i] > offset }) - 1
+
+	return Position{Line: line, Character: utf16Len(d.text[d.lines[line]:offset])}
+}
+
+// offset converts an LSP position into a byte offset.
+func (d *document) offset(pos Position) int {
+	if pos.Line >= len(d.lines) {
+		return len(d.text)
+	}
+
+	offset := d.lines[pos.Line]
+
+	for units := 0; units < pos.Character && offset < len(d.text) && d.text[offset] != '\n'; {
+		r, size := utf8.DecodeRuneInString(d.text[offset:])
+		units += len(utf16.Encode([]rune{r}))
+		offset += size
+	}
+
+	return offset
+}
+
+// span returns the range of a node, without the blank space before the next
+// token.
+func (d *document) span(pos, end lexer.Position) Range {
+	stop := clamp(end.Offset, len(d.text))
+	stop = pos.Offset + len(strings.TrimRight(d.text[clamp(pos.Offset, stop):stop], " \t\r\n"))
+
+	return Range{Start: d.position(pos.Offset), End: d.position(stop)}
+}
+
+// name returns the range of the first identifier equal to name at or after
+// pos, or an empty range at pos if there is none.
+func (d *document) name(pos lexer.Position, name string) Range {
+	for from := clamp(pos.Offset, len(d.text)); ; {
+		i := strings.Index(d.text[from:], name)
+		if i < 0 {
+			return Range{Start: d.position(pos.Offset), End: d.position(pos.Offset)}
+		}
+
+		start, end := from+i, from+i+len(name)
+		if (start == 0 || !isIdentByte(d.text[start-1])) && (end == len(d.text) || !isIdentByte(d.text[end])) {
+			return Range{Start: d.position(start), End: d.position(end)}
+		}
+
+		from = end
+	}
+}
+
+// word returns the range of the identifier starting at offset.
+func (d *document) word(offset int) Range {
+	end := clamp(offset, len(d.text))
+	for end < len(d.text) && isIdentByte(d.text[end]) {
+		end++
+	}
+
+	return Range{Start: d.position(offset), End: d.position(end)}
+}
+
+// path returns the nodes that contain the offset, from the program down to the
+// innermost node.
+func (d *document) path(offset int) []any {
+	var out []any
+
+	ast.Inspect(d.prog, func(node any) bool {
+		if node == nil {
+			return false
+		}
+
+		v := reflect.ValueOf(node).Elem()
+
+		// Comment groups have no position of their own.
+		if !v.FieldByName("Pos").IsValid() {
+			return false
+		}
+
+		pos, end := v.FieldByName("Pos").Interface().(lexer.Position), v.FieldByName("EndPos").Interface().(lexer.Position)
+		if offset < pos.Offset || offset > end.Offset {
+			return false
+		}
+
+		out = append(out, node)
+
+		return true
+	})
+
+	return out
+}
+
+// collection returns the collection named name.
+func (d *document) collection(name string) *ast.Collection {
+	for _, node := range d.prog.Nodes {
+		if node.Collection != nil && node.Collection.Name == name {
+			return node.Collection
+		}
+	}
+
+	return nil
+}
+
+// enclosing returns the collection containing the offset, which is the last
+// one starting before it, so that it is found while its code is broken.
+func (d *document) enclosing(offset int) *ast.Collection {
+	var out *ast.Collection
+
+	for _, node := range d.prog.Nodes {
+		if node.Collection != nil && node.Collection.Pos.Offset <= offset {
+			out = node.Collection
+		}
+	}
+
+	return out
+}
+
+func fields(coll *ast.Collection) []*ast.Field {
+	var out []*ast.Field
+
+	for _, item := range coll.Items {
+		if item.Field != nil {
+			out = append(out, item.Field)
+		}
+	}
+
+	return out
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+func utf16Len(s string) int {
+	n := 0
+
+	for _, r := range s {
+		n += len(utf16.Encode([]rune{r}))
+	}
+
+	return n
+}
+
+func clamp(v, max int) int {
+	switch {
+	case v < 0:
+		return 0
+	case v > max:
+		return max
+	default:
+		return v
+	}
+}
@@ -0,0 +1,40 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Polylang-lsp is a language server for Polylang.
+//
+// Usage:
+//
+//	polylang-lsp
+//
+// It speaks the Language Server Protocol over the standard input and output,
+// and provides diagnostics, document symbols, go-to-definition, hover and
+// completion of the members of this.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	os.Exit(run(os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run serves a client until it exits and returns the exit code, which is 0 if
+// the client asked the server to shut down first.
+func run(in io.Reader, out, errOut io.Writer) int {
+	s := newServer(newConn(in, out))
+
+	code, err := s.serve()
+	if err != nil {
+		fmt.Fprintln(errOut, err)
+	}
+
+	return code
+}
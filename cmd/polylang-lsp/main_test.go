@@ -0,0 +1,394 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const (
+	uri    = "file:///test.polylang"
+	source = `collection User {
+    id: string;
+    // Name of the user.
+    name?: string;
+    profile: {
+        age: number;
+    };
+    account: Account;
+
+    @index(name, profile.age);
+
+    function setName(name: string) {
+        this.name = name;
+        this.
+    }
+}
+
+@public
+collection Account {
+    id: string;
+}
+`
+)
+
+// client drives a server running in the same process.
+type client struct {
+	t        *testing.T
+	conn     *conn
+	id       int
+	done     chan int
+	messages chan *message
+
+	// notifications are the notifications received while waiting for
+	// responses.
+	notifications []*message
+}
+
+func newClient(t *testing.T) *client {
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+
+	c := &client{
+		t:    t,
+		conn: newConn(outR, inW),
+		done: make(chan int, 1),
+		// The server blocks while its output is not read, so the messages
+		// are read as they come and buffered until they are waited for.
+		messages: make(chan *message, 64),
+	}
+
+	go func() {
+		c.done <- run(inR, outW, io.Discard)
+		outW.Close()
+	}()
+
+	go func() {
+		defer close(c.messages)
+
+		for {
+			msg, err := c.conn.read()
+			if err != nil {
+				return
+			}
+
+			c.messages <- msg
+		}
+	}()
+
+	t.Cleanup(func() { inW.Close() })
+
+	c.request("initialize", map[string]any{}, nil)
+	c.notify("initialized", map[string]any{})
+
+	return c
+}
+
+func (c *client) notify(method string, params any) {
+	if err := c.conn.notify(method, params); err != nil {
+		c.t.Fatal("error: writing notification: ", err)
+	}
+}
+
+func (c *client) request(method string, params, result any) *rpcError {
+	c.id++
+	id := json.RawMessage(strconv.Itoa(c.id))
+
+	data, err := json.Marshal(params)
+	if err != nil {
+		c.t.Fatal("error: marshalling params: ", err)
+	}
+
+	if err := c.conn.write(&message{ID: id, Method: method, Params: data}); err != nil {
+		c.t.Fatal("error: writing request: ", err)
+	}
+
+	for msg := range c.messages {
+
+		if msg.ID == nil {
+			c.notifications = append(c.notifications, msg)
+
+			continue
+		}
+
+		if string(msg.ID) != string(id) {
+			c.t.Fatal("error: unexpected response id: ", string(msg.ID))
+		}
+
+		if msg.Error != nil {
+			return msg.Error
+		}
+
+		if result != nil {
+			if err := json.Unmarshal(msg.Result, result); err != nil {
+				c.t.Fatal("error: unmarshalling result: ", err)
+			}
+		}
+
+		return nil
+	}
+
+	c.t.Fatal("error: connection closed")
+
+	return nil
+}
+
+// diagnostics returns the last diagnostics published for the document.
+func (c *client) diagnostics() []Diagnostic {
+	// Notifications are only read while waiting for a response.
+	c.request("shutdown", nil, nil)
+
+	for i := len(c.notifications) - 1; i >= 0; i-- {
+		if c.notifications[i].Method != "textDocument/publishDiagnostics" {
+			continue
+		}
+
+		var params PublishDiagnosticsParams
+		if err := json.Unmarshal(c.notifications[i].Params, &params); err != nil {
+			c.t.Fatal("error: unmarshalling diagnostics: ", err)
+		}
+
+		return params.Diagnostics
+	}
+
+	c.t.Fatal("error: no diagnostics were published")
+
+	return nil
+}
+
+func (c *client) open(text string) {
+	c.notify("textDocument/didOpen", DidOpenTextDocumentParams{
+		TextDocument: TextDocumentItem{URI: uri, Text: text},
+	})
+}
+
+// at returns the params of a request at the n-th occurrence of substr in the
+// source, offset by delta bytes.
+func at(t *testing.T, substr string, n, delta int) TextDocumentPositionParams {
+	offset := -1
+
+	for i := 0; i <= n; i++ {
+		j := strings.Index(source[offset+1:], substr)
+		if j < 0 {
+			t.Fatal("error: substring not found: ", substr)
+		}
+
+		offset += j + 1
+	}
+
+	return TextDocumentPositionParams{
+		TextDocument: TextDocumentIdentifier{URI: uri},
+		Position:     newDocument(uri, source).position(offset + delta),
+	}
+}
+
+func TestServer_Exit(t *testing.T) {
+	c := newClient(t)
+
+	c.request("shutdown", nil, nil)
+	c.notify("exit", nil)
+
+	if code := <-c.done; code != 0 {
+		t.Fatal("error: unexpected exit code: ", code)
+	}
+}
+
+func TestServer_MethodNotFound(t *testing.T) {
+	c := newClient(t)
+
+	err := c.request("textDocument/unknown", map[string]any{}, nil)
+	if err == nil || err.Code != codeMethodNotFound {
+		t.Fatal("error: unexpected error: ", err)
+	}
+}
+
+func TestServer_Diagnostics(t *testing.T) {
+	tests := map[string]struct {
+		text  string
+		want  []string
+		start Position
+	}{
+		"OK": {text: strings.Replace(source, "this.\n", "", 1)},
+		"Syntax": {
+			text:  "collection User {\n    id: string\n}\n",
+			want:  []string{""},
+			start: Position{Line: 2, Character: 0},
+		},
+		"Check": {
+			text:  "collection User {\n    id: string;\n    id: string;\n}\n",
+			want:  []string{"duplicate-field"},
+			start: Position{Line: 2, Character: 4},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := newClient(t)
+			c.open(test.text)
+
+			got := c.diagnostics()
+
+			if len(got) != len(test.want) {
+				t.Fatalf("error: expected %d diagnostics, got %+v", len(test.want), got)
+			}
+
+			for i, diagnostic := range got {
+				if diagnostic.Code != test.want[i] || diagnostic.Range.Start != test.start {
+					t.Fatalf("error: unexpected diagnostic: %+v", diagnostic)
+				}
+			}
+		})
+	}
+}
+
+func TestServer_DocumentSymbol(t *testing.T) {
+	c := newClient(t)
+	c.open(source)
+
+	var got []DocumentSymbol
+
+	if err := c.request("textDocument/documentSymbol", DocumentSymbolParams{
+		TextDocument: TextDocumentIdentifier{URI: uri},
+	}, &got); err != nil {
+		t.Fatal("error: unexpected error: ", err)
+	}
+
+	if len(got) != 2 || got[0].Name != "User" || got[1].Name != "Account" {
+		t.Fatalf("error: unexpected symbols: %+v", got)
+	}
+
+	if want := (Position{Line: 18, Character: 11}); got[1].SelectionRange.Start != want {
+		t.Fatalf("error: unexpected selection range: %+v", got[1].SelectionRange)
+	}
+
+	var names []string
+	for _, child := range got[0].Children {
+		names = append(names, child.Name)
+	}
+
+	if strings.Join(names, ",") != "id,name,profile,account,setName" {
+		t.Fatal("error: unexpected children: ", names)
+	}
+
+	if profile := got[0].Children[2]; len(profile.Children) != 1 || profile.Children[0].Name != "age" {
+		t.Fatalf("error: unexpected object field symbol: %+v", profile)
+	}
+
+	if fn := got[0].Children[4]; fn.Kind != SymbolKindMethod || fn.Detail != "(name: string)" {
+		t.Fatalf("error: unexpected function symbol: %+v", fn)
+	}
+}
+
+func TestServer_Definition(t *testing.T) {
+	tests := map[string]struct {
+		params TextDocumentPositionParams
+		want   Position
+	}{
+		"Foreign":      {params: at(t, "Account", 0, 2), want: Position{Line: 18, Character: 11}},
+		"Index":        {params: at(t, "name", 1, 1), want: Position{Line: 3, Character: 4}},
+		"Nested index": {params: at(t, "profile.age", 0, 9), want: Position{Line: 5, Character: 8}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := newClient(t)
+			c.open(source)
+
+			var got *Location
+
+			if err := c.request("textDocument/definition", test.params, &got); err != nil {
+				t.Fatal("error: unexpected error: ", err)
+			}
+
+			if got == nil || got.URI != uri || got.Range.Start != test.want {
+				t.Fatalf("error: unexpected location: %+v", got)
+			}
+		})
+	}
+}
+
+func TestServer_Hover(t *testing.T) {
+	tests := map[string]struct {
+		params TextDocumentPositionParams
+		want   string
+	}{
+		"Field":  {params: at(t, "name?", 0, 1), want: "```polylang\nname?: string\n```\n\nName of the user.\n"},
+		"Member": {params: at(t, "this.name", 0, 6), want: "```polylang\nname?: string\n```\n\nName of the user.\n"},
+		"Index":  {params: at(t, "profile.age", 0, 9), want: "```polylang\nage: number\n```"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := newClient(t)
+			c.open(source)
+
+			var got *Hover
+
+			if err := c.request("textDocument/hover", test.params, &got); err != nil {
+				t.Fatal("error: unexpected error: ", err)
+			}
+
+			if got == nil || got.Contents.Value != test.want {
+				t.Fatalf("error: unexpected hover: %+v", got)
+			}
+		})
+	}
+}
+
+func TestServer_Completion(t *testing.T) {
+	tests := map[string]struct {
+		text   string
+		params TextDocumentPositionParams
+		want   string
+	}{
+		"This": {
+			params: at(t, "this.\n", 0, 5),
+			want:   "id,name,profile,account,setName",
+		},
+		"Object": {
+			text:   strings.Replace(source, "this.\n", "this.profile.\n", 1),
+			params: at(t, "this.\n", 0, 5),
+			want:   "age",
+		},
+		"None": {
+			params: at(t, "name = name", 0, 7),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := newClient(t)
+
+			if test.text == "" {
+				c.open(source)
+			} else {
+				c.open(test.text)
+				test.params.Position.Character += len("profile.")
+			}
+
+			var got []CompletionItem
+
+			if err := c.request("textDocument/completion", test.params, &got); err != nil {
+				t.Fatal("error: unexpected error: ", err)
+			}
+
+			var labels []string
+			for _, item := range got {
+				labels = append(labels, item.Label)
+			}
+
+			if strings.Join(labels, ",") != test.want {
+				t.Fatal("error: unexpected completion: ", labels)
+			}
+		})
+	}
+}
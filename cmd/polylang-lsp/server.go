@@ -0,0 +1,469 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/durudex/go-polylang/ast"
+	"github.com/durudex/go-polylang/printer"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+type server struct {
+	conn      *conn
+	documents map[string]*document
+	shutdown  bool
+}
+
+func newServer(c *conn) *server {
+	return &server{conn: c, documents: make(map[string]*document)}
+}
+
+// handler handles a request and returns its result.
+type handler func(s *server, params json.RawMessage) (any, error)
+
+var handlers = map[string]handler{
+	"initialize":                  (*server).initialize,
+	"shutdown":                    (*server).shutdownRequest,
+	"textDocument/documentSymbol": (*server).documentSymbol,
+	"textDocument/definition":     (*server).definition,
+	"textDocument/hover":          (*server).hover,
+	"textDocument/completion":     (*server).completion,
+}
+
+// serve handles messages until the client exits or the connection is closed.
+func (s *server) serve() (int, error) {
+	for {
+		msg, err := s.conn.read()
+
+		var rerr *rpcError
+
+		switch {
+		case errors.Is(err, io.EOF):
+			return 1, nil
+		case errors.As(err, &rerr):
+			if err := s.conn.reply(json.RawMessage("null"), nil, rerr); err != nil {
+				return 1, err
+			}
+
+			continue
+		case err != nil:
+			return 1, err
+		}
+
+		if msg.Method == "exit" {
+			if s.shutdown {
+				return 0, nil
+			}
+
+			return 1, nil
+		}
+
+		if err := s.handle(msg); err != nil {
+			return 1, err
+		}
+	}
+}
+
+func (s *server) handle(msg *message) error {
+	// Notifications have no id and get no response, even if they fail.
+	if msg.ID == nil {
+		return s.notification(msg.Method, msg.Params)
+	}
+
+	h, ok := handlers[msg.Method]
+	if !ok {
+		return s.conn.reply(msg.ID, nil, &rpcError{
+			Code: codeMethodNotFound, Message: "method not found: " + msg.Method,
+		})
+	}
+
+	result, err := h(s, msg.Params)
+
+	return s.conn.reply(msg.ID, result, err)
+}
+
+func (s *server) notification(method string, params json.RawMessage) error {
+	switch method {
+	case "textDocument/didOpen":
+		var p DidOpenTextDocumentParams
+		if json.Unmarshal(params, &p) != nil {
+			return nil
+		}
+
+		return s.update(p.TextDocument.URI, p.TextDocument.Text)
+	case "textDocument/didChange":
+		var p DidChangeTextDocumentParams
+		if json.Unmarshal(params, &p) != nil || len(p.ContentChanges) == 0 {
+			return nil
+		}
+
+		// The server asks for full synchronization, so the last change holds
+		// the whole text.
+		return s.update(p.TextDocument.URI, p.ContentChanges[len(p.ContentChanges)-1].Text)
+	case "textDocument/didClose":
+		var p DidCloseTextDocumentParams
+		if json.Unmarshal(params, &p) != nil {
+			return nil
+		}
+
+		delete(s.documents, p.TextDocument.URI)
+
+		return s.conn.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+			URI: p.TextDocument.URI, Diagnostics: []Diagnostic{},
+		})
+	}
+
+	return nil
+}
+
+// update parses the new text of a document and publishes its diagnostics.
+func (s *server) update(uri, text string) error {
+	d := newDocument(uri, text)
+	s.documents[uri] = d
+
+	return s.conn.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+		URI: uri, Diagnostics: d.diagnostics(),
+	})
+}
+
+func (s *server) initialize(json.RawMessage) (any, error) {
+	return InitializeResult{
+		Capabilities: ServerCapabilities{
+			TextDocumentSync:       TextDocumentSyncKindFull,
+			DocumentSymbolProvider: true,
+			DefinitionProvider:     true,
+			HoverProvider:          true,
+			CompletionProvider:     CompletionOptions{TriggerCharacters: []string{"."}},
+		},
+		ServerInfo: ServerInfo{Name: "polylang-lsp"},
+	}, nil
+}
+
+func (s *server) shutdownRequest(json.RawMessage) (any, error) {
+	s.shutdown = true
+
+	return nil, nil
+}
+
+// document decodes the params of a request and returns the document they
+// refer to.
+func (s *server) document(params json.RawMessage, v any, uri func() string) (*document, error) {
+	if err := json.Unmarshal(params, v); err != nil {
+		return nil, &rpcError{Code: codeInvalidParams, Message: err.Error()}
+	}
+
+	d, ok := s.documents[uri()]
+	if !ok {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "unknown document: " + uri()}
+	}
+
+	return d, nil
+}
+
+func (s *server) position(params json.RawMessage) (*document, int, error) {
+	var p TextDocumentPositionParams
+
+	d, err := s.document(params, &p, func() string { return p.TextDocument.URI })
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return d, d.offset(p.Position), nil
+}
+
+func (s *server) documentSymbol(params json.RawMessage) (any, error) {
+	var p DocumentSymbolParams
+
+	d, err := s.document(params, &p, func() string { return p.TextDocument.URI })
+	if err != nil {
+		return nil, err
+	}
+
+	out := []DocumentSymbol{}
+
+	for _, node := range d.prog.Nodes {
+		switch {
+		case node.Collection != nil:
+			coll := node.Collection
+			symbol := DocumentSymbol{
+				Name:           coll.Name,
+				Kind:           SymbolKindClass,
+				Range:          d.span(coll.Pos, coll.EndPos),
+				SelectionRange: d.name(nameStart(coll.Pos, coll.Decorators), coll.Name),
+			}
+
+			for _, item := range coll.Items {
+				switch {
+				case item.Field != nil:
+					symbol.Children = append(symbol.Children, d.fieldSymbol(item.Field))
+				case item.Function != nil:
+					symbol.Children = append(symbol.Children, d.functionSymbol(item.Function, SymbolKindMethod))
+				}
+			}
+
+			out = append(out, symbol)
+		case node.Function != nil:
+			out = append(out, d.functionSymbol(node.Function, SymbolKindFunction))
+		}
+	}
+
+	return out, nil
+}
+
+func (d *document) fieldSymbol(field *ast.Field) DocumentSymbol {
+	symbol := DocumentSymbol{
+		Name:           field.Name,
+		Detail:         printer.TypeString(&field.Type),
+		Kind:           SymbolKindField,
+		Range:          d.span(field.Pos, field.EndPos),
+		SelectionRange: d.name(field.Pos, field.Name),
+	}
+
+	for _, f := range field.Type.Object {
+		symbol.Children = append(symbol.Children, d.fieldSymbol(f))
+	}
+
+	return symbol
+}
+
+func (d *document) functionSymbol(fn *ast.Function, kind SymbolKind) DocumentSymbol {
+	return DocumentSymbol{
+		Name:           fn.Name,
+		Detail:         signature(fn),
+		Kind:           kind,
+		Range:          d.span(fn.Pos, fn.EndPos),
+		SelectionRange: d.name(fn.Pos, fn.Name),
+	}
+}
+
+// nameStart returns the position after the decorators of a collection, so that
+// the search for its name does not stop at a decorator argument.
+func nameStart(pos lexer.Position, decorators []*ast.Decorator) lexer.Position {
+	if len(decorators) != 0 {
+		return decorators[len(decorators)-1].EndPos
+	}
+
+	return pos
+}
+
+func (s *server) definition(params json.RawMessage) (any, error) {
+	d, offset, err := s.position(params)
+	if err != nil {
+		return nil, err
+	}
+
+	path := d.path(offset)
+
+	for i := len(path) - 1; i >= 0; i-- {
+		switch node := path[i].(type) {
+		case *ast.Type:
+			if node.Foreign == "" {
+				continue
+			}
+
+			for _, doc := range s.sorted(d) {
+				if coll := doc.collection(node.Foreign); coll != nil {
+					return Location{
+						URI:   doc.uri,
+						Range: doc.name(nameStart(coll.Pos, coll.Decorators), coll.Name),
+					}, nil
+				}
+			}
+
+			return nil, nil
+		case *ast.IndexField:
+			if coll := d.enclosing(offset); coll != nil {
+				if field := ast.LookupField(fields(coll), strings.Split(string(node.Name), ".")); field != nil {
+					return Location{URI: d.uri, Range: d.name(field.Pos, field.Name)}, nil
+				}
+			}
+
+			return nil, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// sorted returns the open documents, starting with d.
+func (s *server) sorted(d *document) []*document {
+	out := []*document{d}
+
+	for _, doc := range s.documents {
+		if doc != d {
+			out = append(out, doc)
+		}
+	}
+
+	return out
+}
+
+func (s *server) hover(params json.RawMessage) (any, error) {
+	d, offset, err := s.position(params)
+	if err != nil {
+		return nil, err
+	}
+
+	path := d.path(offset)
+	coll := d.enclosing(offset)
+
+	for i := len(path) - 1; i >= 0; i-- {
+		var field *ast.Field
+
+		switch node := path[i].(type) {
+		case *ast.Field:
+			field = node
+		case *ast.Expression:
+			if node.Member == nil || coll == nil {
+				continue
+			}
+
+			if names, ok := thisPath(node); ok {
+				field = ast.LookupField(fields(coll), names)
+			}
+		case *ast.IndexField:
+			if coll != nil {
+				field = ast.LookupField(fields(coll), strings.Split(string(node.Name), "."))
+			}
+		default:
+			continue
+		}
+
+		if field == nil {
+			return nil, nil
+		}
+
+		value := "```polylang\n" + fieldString(field) + "\n```"
+		if text := field.Doc.Text(); text != "" {
+			value += "\n\n" + text
+		}
+
+		r := d.span(pathSpan(path[i]))
+
+		return Hover{Contents: MarkupContent{Kind: "markdown", Value: value}, Range: &r}, nil
+	}
+
+	return nil, nil
+}
+
+// thisPath returns the names of a member expression on this, such as a and b
+// of this.a.b.
+func thisPath(expr *ast.Expression) ([]string, bool) {
+	switch {
+	case expr.Member != nil:
+		names, ok := thisPath(expr.Member.Object)
+
+		return append(names, expr.Member.Property), ok
+	case expr.Value != nil && expr.Value.Ident != nil:
+		return nil, *expr.Value.Ident == "this"
+	default:
+		return nil, false
+	}
+}
+
+func pathSpan(node any) (lexer.Position, lexer.Position) {
+	switch node := node.(type) {
+	case *ast.Field:
+		return node.Pos, node.EndPos
+	case *ast.Expression:
+		return node.Pos, node.EndPos
+	case *ast.IndexField:
+		return node.Pos, node.EndPos
+	}
+
+	return lexer.Position{}, lexer.Position{}
+}
+
+// member matches a member expression on this that is being typed at the end of
+// a line, capturing the path before the last dot.
+var member = regexp.MustCompile(`\bthis((?:\.\w+)*)\.\w*$`)
+
+func (s *server) completion(params json.RawMessage) (any, error) {
+	d, offset, err := s.position(params)
+	if err != nil {
+		return nil, err
+	}
+
+	out := []CompletionItem{}
+
+	line := d.text[d.lines[d.position(offset).Line]:offset]
+
+	match := member.FindStringSubmatch(line)
+	if match == nil {
+		return out, nil
+	}
+
+	coll := d.enclosing(offset)
+	if coll == nil {
+		return out, nil
+	}
+
+	list := fields(coll)
+
+	if match[1] != "" {
+		field := ast.LookupField(list, strings.Split(match[1][1:], "."))
+		if field == nil {
+			return out, nil
+		}
+
+		list = field.Type.Object
+	}
+
+	for _, field := range list {
+		out = append(out, CompletionItem{
+			Label:  field.Name,
+			Kind:   CompletionItemKindField,
+			Detail: printer.TypeString(&field.Type),
+		})
+	}
+
+	if match[1] == "" {
+		for _, item := range coll.Items {
+			if item.Function != nil {
+				out = append(out, CompletionItem{
+					Label:  item.Function.Name,
+					Kind:   CompletionItemKindMethod,
+					Detail: signature(item.Function),
+				})
+			}
+		}
+	}
+
+	return out, nil
+}
+
+func fieldString(field *ast.Field) string {
+	var b strings.Builder
+
+	// Printing a field cannot fail, as the builder does not.
+	_ = printer.Fprint(&b, field)
+
+	return b.String()
+}
+
+// signature returns the parameters and the return type of a function.
+func signature(fn *ast.Function) string {
+	params := make([]string, len(fn.Parameters))
+	for i, param := range fn.Parameters {
+		params[i] = fieldString(param)
+	}
+
+	out := "(" + strings.Join(params, ", ") + ")"
+	if !fn.ReturnType.IsZero() {
+		out += ": " + printer.TypeString(&fn.ReturnType)
+	}
+
+	return out
+}
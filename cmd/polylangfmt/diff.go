@@ -0,0 +1,186 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// context is the number of unchanged lines shown around every change.
+const context = 3
+
+type edit struct {
+	kind byte // ' ', '-' or '+'
+	line []byte
+}
+
+// diff returns the unified diff between the lines of two texts, or nothing if
+// they are equal.
+func diff(oldName string, old []byte, newName string, new []byte) []byte {
+	edits := lineEdits(splitLines(old), splitLines(new))
+
+	var out bytes.Buffer
+
+	for start := 0; start < len(edits); {
+		// Find the next change and the context around it, merging the changes
+		// that are close enough to share their context.
+		first := start
+		for first < len(edits) && edits[first].kind == ' ' {
+			first++
+		}
+
+		if first == len(edits) {
+			break
+		}
+
+		end := first
+		for i := first; i < len(edits); i++ {
+			if edits[i].kind != ' ' {
+				end = i + 1
+			} else if i-end >= 2*context {
+				break
+			}
+		}
+
+		from := first - context
+		if from < start {
+			from = start
+		}
+
+		to := end + context
+		if to > len(edits) {
+			to = len(edits)
+		}
+
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", oldName, newName)
+		}
+
+		hunk(&out, edits, from, to)
+
+		start = to
+	}
+
+	return out.Bytes()
+}
+
+func hunk(out *bytes.Buffer, edits []edit, from, to int) {
+	// Line numbers are one-based and count the lines before the hunk.
+	oldLine, newLine := 1, 1
+
+	for _, e := range edits[:from] {
+		if e.kind != '+' {
+			oldLine++
+		}
+
+		if e.kind != '-' {
+			newLine++
+		}
+	}
+
+	var oldCount, newCount int
+
+	for _, e := range edits[from:to] {
+		if e.kind != '+' {
+			oldCount++
+		}
+
+		if e.kind != '-' {
+			newCount++
+		}
+	}
+
+	// An empty range refers to the line before it.
+	if oldCount == 0 {
+		oldLine--
+	}
+
+	if newCount == 0 {
+		newLine--
+	}
+
+	fmt.Fprintf(out, "@@ -%d,%d +%d,%d @@\n", oldLine, oldCount, newLine, newCount)
+
+	for _, e := range edits[from:to] {
+		out.WriteByte(e.kind)
+		out.Write(e.line)
+
+		if !bytes.HasSuffix(e.line, []byte("\n")) {
+			out.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// lineEdits returns the shortest edit script turning a into b, computed from
+// their longest common subsequence.
+func lineEdits(a, b [][]byte) []edit {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case bytes.Equal(a[i], b[j]):
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var edits []edit
+
+	i, j := 0, 0
+
+	for i < len(a) && j < len(b) {
+		switch {
+		case bytes.Equal(a[i], b[j]):
+			edits = append(edits, edit{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			edits = append(edits, edit{'-', a[i]})
+			i++
+		default:
+			edits = append(edits, edit{'+', b[j]})
+			j++
+		}
+	}
+
+	for ; i < len(a); i++ {
+		edits = append(edits, edit{'-', a[i]})
+	}
+
+	for ; j < len(b); j++ {
+		edits = append(edits, edit{'+', b[j]})
+	}
+
+	return edits
+}
+
+// splitLines splits the text after every line break, keeping the breaks.
+func splitLines(text []byte) [][]byte {
+	var lines [][]byte
+
+	for len(text) != 0 {
+		i := bytes.IndexByte(text, '\n') + 1
+		if i == 0 {
+			i = len(text)
+		}
+
+		lines = append(lines, text[:i])
+		text = text[i:]
+	}
+
+	return lines
+}
@@ -0,0 +1,55 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package main
+
+import "testing"
+
+var DiffTests = map[string]struct {
+	old  string
+	new  string
+	want string
+}{
+	"Equal": {
+		old:  "a\nb\n",
+		new:  "a\nb\n",
+		want: "",
+	},
+	"Change": {
+		old:  "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+		new:  "1\n2\n3\n4\nfive\n6\n7\n8\n9\n",
+		want: "--- a\n+++ b\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+	},
+	"Separate Hunks": {
+		old: "a\n1\n2\n3\n4\n5\n6\n7\nb\n",
+		new: "A\n1\n2\n3\n4\n5\n6\n7\nB\n",
+		want: "--- a\n+++ b\n@@ -1,4 +1,4 @@\n-a\n+A\n 1\n 2\n 3\n" +
+			"@@ -6,4 +6,4 @@\n 5\n 6\n 7\n-b\n+B\n",
+	},
+	"Insert Into Empty": {
+		old:  "",
+		new:  "a\n",
+		want: "--- a\n+++ b\n@@ -0,0 +1,1 @@\n+a\n",
+	},
+	"Missing Newline": {
+		old:  "a",
+		new:  "a\n",
+		want: "--- a\n+++ b\n@@ -1,1 +1,1 @@\n-a\n\\ No newline at end of file\n+a\n",
+	},
+}
+
+func TestDiff(t *testing.T) {
+	for name, test := range DiffTests {
+		t.Run(name, func(t *testing.T) {
+			got := diff("a", []byte(test.old), "b", []byte(test.new))
+
+			if string(got) != test.want {
+				t.Fatalf("error: diff does not match:\n%s", got)
+			}
+		})
+	}
+}
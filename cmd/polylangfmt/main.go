@@ -0,0 +1,163 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Polylangfmt formats Polylang source files.
+//
+// Usage:
+//
+//	polylangfmt [flags] [path ...]
+//
+// Without paths it formats the standard input. A path names a file, which is
+// formatted whatever its extension, or a directory, which is processed
+// recursively, formatting every .polylang file in it. The flags are:
+//
+//	-d
+//		Do not print reformatted sources to standard output.
+//		Print a diff for each file that differs from its formatted version.
+//	-l
+//		Do not print reformatted sources to standard output.
+//		List the files whose formatting differs.
+//	-w
+//		Do not print reformatted sources to standard output.
+//		Overwrite each file that differs with its formatted version.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/durudex/go-polylang/format"
+)
+
+type options struct {
+	list  bool
+	diff  bool
+	write bool
+}
+
+func main() {
+	var opts options
+
+	flag.BoolVar(&opts.list, "l", false, "list files whose formatting differs")
+	flag.BoolVar(&opts.diff, "d", false, "display diffs instead of rewriting files")
+	flag.BoolVar(&opts.write, "w", false, "write result to source file instead of stdout")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: polylangfmt [flags] [path ...]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	os.Exit(run(opts, flag.Args(), os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run formats the paths, or the input if there are none, and returns the exit
+// code of the command.
+func run(opts options, paths []string, in io.Reader, out, errOut io.Writer) int {
+	if len(paths) == 0 {
+		if opts.write {
+			fmt.Fprintln(errOut, "error: cannot use -w with standard input")
+
+			return 2
+		}
+
+		src, err := io.ReadAll(in)
+		if err == nil {
+			err = process(opts, "<standard input>", src, out)
+		}
+
+		if err != nil {
+			fmt.Fprintln(errOut, err)
+
+			return 2
+		}
+
+		return 0
+	}
+
+	code := 0
+
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			// The extension only selects the files found in directories.
+			if entry.IsDir() || (path != root && filepath.Ext(path) != ".polylang") {
+				return nil
+			}
+
+			if err := processFile(opts, path, out); err != nil {
+				fmt.Fprintln(errOut, err)
+
+				code = 2
+			}
+
+			return nil
+		})
+		if err != nil {
+			fmt.Fprintln(errOut, err)
+
+			code = 2
+		}
+	}
+
+	return code
+}
+
+func processFile(opts options, path string, out io.Writer) error {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	return process(opts, path, src, out)
+}
+
+func process(opts options, filename string, src []byte, out io.Writer) error {
+	res, err := format.Source(filename, src)
+	if err != nil {
+		return err
+	}
+
+	if !opts.list && !opts.diff && !opts.write {
+		_, err := out.Write(res)
+
+		return err
+	}
+
+	if bytes.Equal(src, res) {
+		return nil
+	}
+
+	if opts.list {
+		fmt.Fprintln(out, filename)
+	}
+
+	if opts.write {
+		info, err := os.Stat(filename)
+		if err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(filename, res, info.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+
+	if opts.diff {
+		fmt.Fprintf(out, "diff %s polylangfmt/%s\n", filename, filename)
+		out.Write(diff("a/"+filename, src, "b/"+filename, res)) //nolint:errcheck
+	}
+
+	return nil
+}
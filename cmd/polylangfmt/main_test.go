@@ -0,0 +1,150 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	unformatted = "collection Test{id:string; // id\n}\n"
+	formatted   = "collection Test {\n    id: string; // id\n}\n"
+)
+
+func writeFixture(t *testing.T, content string) (string, string) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.polylang")
+
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal("error: writing fixture: ", err)
+	}
+
+	return dir, path
+}
+
+func TestRun_Stdin(t *testing.T) {
+	var out, errOut bytes.Buffer
+
+	code := run(options{}, nil, strings.NewReader(unformatted), &out, &errOut)
+	if code != 0 {
+		t.Fatal("error: unexpected exit code: ", errOut.String())
+	}
+
+	if out.String() != formatted {
+		t.Fatal("error: output does not match: ", out.String())
+	}
+}
+
+func TestRun_List(t *testing.T) {
+	dir, path := writeFixture(t, unformatted)
+
+	if err := os.WriteFile(filepath.Join(dir, "ignored.txt"), []byte("{"), 0o644); err != nil {
+		t.Fatal("error: writing fixture: ", err)
+	}
+
+	var out, errOut bytes.Buffer
+
+	if code := run(options{list: true}, []string{dir}, nil, &out, &errOut); code != 0 {
+		t.Fatal("error: unexpected exit code: ", errOut.String())
+	}
+
+	if out.String() != path+"\n" {
+		t.Fatal("error: listed files do not match: ", out.String())
+	}
+}
+
+func TestRun_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "contract.txt")
+
+	if err := os.WriteFile(path, []byte(unformatted), 0o644); err != nil {
+		t.Fatal("error: writing fixture: ", err)
+	}
+
+	var out, errOut bytes.Buffer
+
+	if code := run(options{list: true}, []string{path}, nil, &out, &errOut); code != 0 {
+		t.Fatal("error: unexpected exit code: ", errOut.String())
+	}
+
+	if out.String() != path+"\n" {
+		t.Fatal("error: listed files do not match: ", out.String())
+	}
+}
+
+func TestRun_Write(t *testing.T) {
+	_, path := writeFixture(t, unformatted)
+
+	var out, errOut bytes.Buffer
+
+	if code := run(options{write: true}, []string{path}, nil, &out, &errOut); code != 0 {
+		t.Fatal("error: unexpected exit code: ", errOut.String())
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal("error: reading fixture: ", err)
+	}
+
+	if string(got) != formatted || out.Len() != 0 {
+		t.Fatal("error: written file does not match: ", string(got))
+	}
+}
+
+func TestRun_Diff(t *testing.T) {
+	_, path := writeFixture(t, unformatted)
+
+	var out, errOut bytes.Buffer
+
+	if code := run(options{diff: true}, []string{path}, nil, &out, &errOut); code != 0 {
+		t.Fatal("error: unexpected exit code: ", errOut.String())
+	}
+
+	want := "diff " + path + " polylangfmt/" + path + "\n" +
+		"--- a/" + path + "\n+++ b/" + path + "\n" +
+		"@@ -1,2 +1,3 @@\n" +
+		"-collection Test{id:string; // id\n" +
+		"+collection Test {\n" +
+		"+    id: string; // id\n" +
+		" }\n"
+
+	if out.String() != want {
+		t.Fatal("error: diff does not match: ", out.String())
+	}
+}
+
+func TestRun_Formatted(t *testing.T) {
+	_, path := writeFixture(t, formatted)
+
+	var out, errOut bytes.Buffer
+
+	if code := run(options{list: true, diff: true}, []string{path}, nil, &out, &errOut); code != 0 {
+		t.Fatal("error: unexpected exit code: ", errOut.String())
+	}
+
+	if out.Len() != 0 {
+		t.Fatal("error: formatted file is reported: ", out.String())
+	}
+}
+
+func TestRun_Error(t *testing.T) {
+	_, path := writeFixture(t, "collection {")
+
+	var out, errOut bytes.Buffer
+
+	if code := run(options{list: true}, []string{path}, nil, &out, &errOut); code != 2 {
+		t.Fatal("error: unexpected exit code: ", code)
+	}
+
+	if !strings.Contains(errOut.String(), path) {
+		t.Fatal("error: error does not name the file: ", errOut.String())
+	}
+}
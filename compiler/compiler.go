@@ -0,0 +1,319 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Package compiler compiles a parsed program into the collection metadata
+// produced by the Polybase tooling.
+package compiler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/durudex/go-polylang/ast"
+	"github.com/durudex/go-polylang/metadata"
+	"github.com/durudex/go-polylang/printer"
+)
+
+// ReturnValueName is the name given to the return value of every method, as
+// Polylang functions can not name it.
+const ReturnValueName = "_"
+
+// Compile returns the metadata of every collection in the program, placed in
+// the namespace. Functions declared outside of collections have no metadata
+// and are skipped.
+func Compile(prog *ast.Program, namespace string) (metadata.Root, error) {
+	root := metadata.Root{}
+
+	for _, node := range prog.Nodes {
+		if node.Collection == nil {
+			continue
+		}
+
+		coll, err := Collection(node.Collection, namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		n, err := anyKind[metadata.Node]("collection", coll)
+		if err != nil {
+			return nil, err
+		}
+
+		root = append(root, n)
+	}
+
+	return root, nil
+}
+
+// Collections returns the metadata of the collections of a node, which must be
+// an *ast.Program, an *ast.Collection, a metadata.Root or a
+// *metadata.Collection. AST nodes are compiled in an empty namespace, so that
+// code generators can take either form.
+func Collections(node any) ([]*metadata.Collection, error) {
+	switch n := node.(type) {
+	case *ast.Program:
+		root, err := Compile(n, "")
+		if err != nil {
+			return nil, err
+		}
+
+		return Collections(root)
+	case *ast.Collection:
+		coll, err := Collection(n, "")
+		if err != nil {
+			return nil, err
+		}
+
+		return []*metadata.Collection{coll}, nil
+	case metadata.Root:
+		out := make([]*metadata.Collection, 0, len(n))
+
+		for _, node := range n {
+			coll, ok, err := node.Collection()
+			if err != nil {
+				return nil, err
+			}
+
+			if !ok {
+				return nil, fmt.Errorf("unknown node kind %q", node.Kind)
+			}
+
+			out = append(out, coll)
+		}
+
+		return out, nil
+	case *metadata.Collection:
+		return []*metadata.Collection{n}, nil
+	default:
+		return nil, fmt.Errorf("unsupported node type %T", node)
+	}
+}
+
+// Collection compiles a single collection placed in the namespace.
+func Collection(coll *ast.Collection, namespace string) (*metadata.Collection, error) {
+	out := &metadata.Collection{
+		Namespace:  metadata.Namespace{Value: namespace},
+		Name:       coll.Name,
+		Attributes: []metadata.CollectionAttribute{},
+	}
+
+	for _, decorator := range coll.Decorators {
+		directive, err := Directive(decorator)
+		if err != nil {
+			return nil, err
+		}
+
+		attr, err := anyKind[metadata.CollectionAttribute]("directive", directive)
+		if err != nil {
+			return nil, err
+		}
+
+		out.Attributes = append(out.Attributes, attr)
+	}
+
+	for _, item := range coll.Items {
+		attr, err := collectionAttribute(item)
+		if err != nil {
+			return nil, fmt.Errorf("%s: collection %s: %w", item.Pos, coll.Name, err)
+		}
+
+		out.Attributes = append(out.Attributes, attr)
+	}
+
+	return out, nil
+}
+
+func collectionAttribute(item *ast.Item) (metadata.CollectionAttribute, error) {
+	switch {
+	case item.Field != nil:
+		prop, err := Property(item.Field, item.Decorators)
+		if err != nil {
+			return metadata.CollectionAttribute{}, err
+		}
+
+		return anyKind[metadata.CollectionAttribute]("property", prop)
+	case item.Index != nil:
+		return anyKind[metadata.CollectionAttribute]("index", Index(item.Index))
+	case item.Function != nil:
+		method, err := Method(item.Function, item.Decorators)
+		if err != nil {
+			return metadata.CollectionAttribute{}, err
+		}
+
+		return anyKind[metadata.CollectionAttribute]("method", method)
+	default:
+		return metadata.CollectionAttribute{}, fmt.Errorf("empty item")
+	}
+}
+
+// Property compiles a field with the decorators placed before it.
+func Property(field *ast.Field, decorators []*ast.Decorator) (*metadata.Property, error) {
+	typ, err := Type(&field.Type)
+	if err != nil {
+		return nil, fmt.Errorf("property %s: %w", field.Name, err)
+	}
+
+	out := &metadata.Property{
+		Name:       field.Name,
+		Type:       typ,
+		Directives: []metadata.Directive{},
+		Required:   !field.Optional,
+	}
+
+	for _, decorator := range decorators {
+		directive, err := Directive(decorator)
+		if err != nil {
+			return nil, err
+		}
+
+		out.Directives = append(out.Directives, *directive)
+	}
+
+	return out, nil
+}
+
+func Index(index *ast.Index) *metadata.Index {
+	out := &metadata.Index{Fields: []metadata.IndexField{}}
+
+	for _, field := range index.Fields {
+		out.Fields = append(out.Fields, metadata.IndexField{
+			Direction: metadata.Order(field.Order.String()),
+			FieldPath: strings.Split(string(field.Name), "."),
+		})
+	}
+
+	return out
+}
+
+// Directive compiles a decorator, whose arguments are references to fields.
+func Directive(decorator *ast.Decorator) (*metadata.Directive, error) {
+	out := &metadata.Directive{
+		Name:      decorator.Name.String(),
+		Arguments: []metadata.DirectiveArgument{},
+	}
+
+	for _, arg := range decorator.Arguments {
+		ref, err := anyKind[metadata.DirectiveArgument]("fieldreference", &metadata.FieldReference{
+			Path: strings.Split(string(arg), "."),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		out.Arguments = append(out.Arguments, ref)
+	}
+
+	return out, nil
+}
+
+// Method compiles a function with the decorators placed before it. The code
+// of the method is its canonically printed statements.
+func Method(fn *ast.Function, decorators []*ast.Decorator) (*metadata.Method, error) {
+	out := &metadata.Method{
+		Name:       fn.Name,
+		Attributes: []metadata.MethodAttribute{},
+	}
+
+	for _, decorator := range decorators {
+		directive, err := Directive(decorator)
+		if err != nil {
+			return nil, err
+		}
+
+		attr, err := anyKind[metadata.MethodAttribute]("directive", directive)
+		if err != nil {
+			return nil, err
+		}
+
+		out.Attributes = append(out.Attributes, attr)
+	}
+
+	for _, param := range fn.Parameters {
+		typ, err := Type(&param.Type)
+		if err != nil {
+			return nil, fmt.Errorf("method %s: parameter %s: %w", fn.Name, param.Name, err)
+		}
+
+		attr, err := anyKind[metadata.MethodAttribute]("parameter", &metadata.Parameter{
+			Name:     param.Name,
+			Type:     typ,
+			Required: !param.Optional,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		out.Attributes = append(out.Attributes, attr)
+	}
+
+	if !fn.ReturnType.IsZero() {
+		typ, err := Type(&fn.ReturnType)
+		if err != nil {
+			return nil, fmt.Errorf("method %s: return type: %w", fn.Name, err)
+		}
+
+		attr, err := anyKind[metadata.MethodAttribute]("returnvalue", &metadata.ReturnValue{
+			Name: ReturnValueName,
+			Type: typ,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		out.Attributes = append(out.Attributes, attr)
+	}
+
+	code, err := Code(fn.Statements)
+	if err != nil {
+		return nil, fmt.Errorf("method %s: %w", fn.Name, err)
+	}
+
+	out.Code = code
+
+	return out, nil
+}
+
+// Code prints the statements of a method body separated by spaces.
+func Code(statements []*ast.Statement) (string, error) {
+	var buf bytes.Buffer
+
+	for i, stmt := range statements {
+		if i != 0 {
+			buf.WriteByte(' ')
+		}
+
+		if err := printer.Fprint(&buf, stmt); err != nil {
+			return "", err
+		}
+	}
+
+	return buf.String(), nil
+}
+
+// anyKind marshals v into a JSON object that starts with the kind, which is
+// how every polymorphic metadata value is told apart.
+func anyKind[T ~struct {
+	Kind  string
+	Value json.RawMessage
+}](kind string, v any) (T, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return T{}, err
+	}
+
+	value := []byte(`{"kind":` + strconv.Quote(kind))
+	if len(data) > 2 {
+		value = append(append(value, ','), data[1:]...)
+	} else {
+		value = append(value, '}')
+	}
+
+	return T{Kind: kind, Value: value}, nil
+}
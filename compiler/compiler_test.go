@@ -0,0 +1,183 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package compiler_test
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/durudex/go-polylang/compiler"
+	"github.com/durudex/go-polylang/internal/jsontest"
+	"github.com/durudex/go-polylang/parser"
+)
+
+func TestCompile(t *testing.T) {
+	prog, err := parser.Parse("fixtures/users.polylang")
+	if err != nil {
+		t.Fatal("error: parsing polylang code: ", err)
+	}
+
+	root, err := compiler.Compile(prog, "Program")
+	if err != nil {
+		t.Fatal("error: compiling program: ", err)
+	}
+
+	got, err := json.Marshal(root)
+	if err != nil {
+		t.Fatal("error: marshal json: ", err)
+	}
+
+	want, err := os.ReadFile("../metadata/fixtures/collection.json")
+	if err != nil {
+		t.Fatal("error: reading fixtures file: ", err)
+	}
+
+	if !reflect.DeepEqual(jsontest.Normalize(t, got), jsontest.RenameReturnValues(jsontest.Normalize(t, want))) {
+		t.Fatalf("error: metadata does not match:\n%s", got)
+	}
+}
+
+var CompileTests = map[string]struct {
+	code string
+	want string
+}{
+	"Function": {
+		code: "function helper() {}",
+		want: `[]`,
+	},
+	"Types": {
+		code: "collection T { a: string[]; b: map<string, T>; c: { d?: bytes; }; e: record; f: boolean; }",
+		want: `[{"kind":"collection","namespace":{"kind":"namespace","value":"ns"},"name":"T","attributes":[` +
+			`{"kind":"property","name":"a","type":{"kind":"array","value":{"kind":"primitive","value":"string"}},"directives":[],"required":true},` +
+			`{"kind":"property","name":"b","type":{"kind":"map","key":{"kind":"primitive","value":"string"},"value":{"kind":"foreignrecord","collection":"T"}},"directives":[],"required":true},` +
+			`{"kind":"property","name":"c","type":{"kind":"object","fields":[{"name":"d","type":{"kind":"primitive","value":"bytes"},"required":false}]},"directives":[],"required":true},` +
+			`{"kind":"property","name":"e","type":{"kind":"record"},"directives":[],"required":true},` +
+			`{"kind":"property","name":"f","type":{"kind":"primitive","value":"boolean"},"directives":[],"required":true}]}]`,
+	},
+	"Property": {
+		code: "collection T { @read @delegate owner: PublicKey; }",
+		want: `[{"kind":"collection","namespace":{"kind":"namespace","value":"ns"},"name":"T","attributes":[` +
+			`{"kind":"property","name":"owner","type":{"kind":"publickey"},"directives":[` +
+			`{"name":"read","arguments":[]},{"name":"delegate","arguments":[]}],"required":true}]}]`,
+	},
+	"Index": {
+		code: "collection T { @index([a, desc], b.c); }",
+		want: `[{"kind":"collection","namespace":{"kind":"namespace","value":"ns"},"name":"T","attributes":[` +
+			`{"kind":"index","fields":[{"direction":"desc","fieldPath":["a"]},{"direction":"asc","fieldPath":["b","c"]}]}]}]`,
+	},
+	"Method": {
+		code: "collection T { @read @call(owner) function f(a: number) { while (a > 0) { a -= 1; } } }",
+		want: `[{"kind":"collection","namespace":{"kind":"namespace","value":"ns"},"name":"T","attributes":[` +
+			`{"kind":"method","name":"f","attributes":[` +
+			`{"kind":"directive","name":"read","arguments":[]},` +
+			`{"kind":"directive","name":"call","arguments":[{"kind":"fieldreference","path":["owner"]}]},` +
+			`{"kind":"parameter","name":"a","type":{"kind":"primitive","value":"number"},"required":true}],` +
+			`"code":"while (a > 0) {\n    a -= 1;\n}"}]}]`,
+	},
+}
+
+func TestCompile_Code(t *testing.T) {
+	for name, test := range CompileTests {
+		t.Run(name, func(t *testing.T) {
+			prog, err := parser.Must.ParseString("", test.code)
+			if err != nil {
+				t.Fatal("error: parsing polylang code: ", err)
+			}
+
+			root, err := compiler.Compile(prog, "ns")
+			if err != nil {
+				t.Fatal("error: compiling program: ", err)
+			}
+
+			got, err := json.Marshal(root)
+			if err != nil {
+				t.Fatal("error: marshal json: ", err)
+			}
+
+			if !reflect.DeepEqual(jsontest.Normalize(t, got), jsontest.Normalize(t, []byte(test.want))) {
+				t.Fatalf("error: metadata does not match:\n%s", got)
+			}
+		})
+	}
+}
+
+func TestCompile_Readable(t *testing.T) {
+	prog, err := parser.Parse("fixtures/users.polylang")
+	if err != nil {
+		t.Fatal("error: parsing polylang code: ", err)
+	}
+
+	root, err := compiler.Compile(prog, "Program")
+	if err != nil {
+		t.Fatal("error: compiling program: ", err)
+	}
+
+	coll, ok, err := root[0].Collection()
+	if err != nil || !ok {
+		t.Fatal("error: node is not collection: ", err)
+	}
+
+	method, ok, err := coll.Attributes[6].Method()
+	if err != nil || !ok {
+		t.Fatal("error: attribute is not method: ", err)
+	}
+
+	directive, ok, err := method.Attributes[0].Directive()
+	if err != nil || !ok {
+		t.Fatal("error: attribute is not directive: ", err)
+	}
+
+	ref, ok, err := directive.Arguments[0].FieldReference()
+	if err != nil || !ok {
+		t.Fatal("error: argument is not field reference: ", err)
+	}
+
+	if !reflect.DeepEqual(ref.Path, []string{"publicKey"}) {
+		t.Fatal("error: field reference does not match")
+	}
+}
+
+func TestCollections(t *testing.T) {
+	prog, err := parser.Parse("fixtures/users.polylang")
+	if err != nil {
+		t.Fatal("error: parsing polylang code: ", err)
+	}
+
+	root, err := compiler.Compile(prog, "")
+	if err != nil {
+		t.Fatal("error: compiling program: ", err)
+	}
+
+	want, err := compiler.Collections(root)
+	if err != nil {
+		t.Fatal("error: collecting metadata collections: ", err)
+	}
+
+	for name, node := range map[string]any{
+		"Program":    prog,
+		"Collection": prog.Nodes[0].Collection,
+		"Metadata":   want[0],
+	} {
+		t.Run(name, func(t *testing.T) {
+			got, err := compiler.Collections(node)
+			if err != nil {
+				t.Fatal("error: collecting metadata collections: ", err)
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Fatal("error: collections do not match")
+			}
+		})
+	}
+
+	if _, err := compiler.Collections(prog.Nodes[0]); err == nil {
+		t.Fatal("error: expected an error for an unsupported node")
+	}
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package compiler
+
+import (
+	"fmt"
+
+	"github.com/durudex/go-polylang/ast"
+	"github.com/durudex/go-polylang/metadata"
+)
+
+var BasicToPrimitive = map[ast.BasicType]metadata.PrimitiveType{
+	ast.String:  metadata.PrimitiveTypeString,
+	ast.Number:  metadata.PrimitiveTypeNumber,
+	ast.Boolean: metadata.PrimitiveTypeBoolean,
+	ast.Bytes:   metadata.PrimitiveTypeBytes,
+}
+
+// Type compiles a type. Arrays are only written with basic element types, so
+// the array flag is only honoured for them.
+func Type(t *ast.Type) (metadata.Type, error) {
+	switch {
+	case t.Basic != 0:
+		elem, err := basicType(t.Basic)
+		if err != nil || !t.Array {
+			return elem, err
+		}
+
+		return anyKind[metadata.Type]("array", &metadata.Array{Value: elem})
+	case t.Map != nil:
+		key, err := basicType(t.Map.Key)
+		if err != nil {
+			return metadata.Type{}, err
+		}
+
+		value, err := Type(&t.Map.Value)
+		if err != nil {
+			return metadata.Type{}, err
+		}
+
+		return anyKind[metadata.Type]("map", &metadata.Map{Key: key, Value: value})
+	case t.Object != nil:
+		obj := &metadata.Object{Fields: []metadata.ObjectField{}}
+
+		for _, field := range t.Object {
+			typ, err := Type(&field.Type)
+			if err != nil {
+				return metadata.Type{}, fmt.Errorf("field %s: %w", field.Name, err)
+			}
+
+			obj.Fields = append(obj.Fields, metadata.ObjectField{
+				Name:     field.Name,
+				Type:     typ,
+				Required: !field.Optional,
+			})
+		}
+
+		return anyKind[metadata.Type]("object", obj)
+	case t.Foreign != "":
+		return anyKind[metadata.Type]("foreignrecord", &metadata.ForeignRecord{
+			Collection: t.Foreign,
+		})
+	default:
+		return metadata.Type{}, fmt.Errorf("missing type")
+	}
+}
+
+func basicType(basic ast.BasicType) (metadata.Type, error) {
+	switch basic {
+	case ast.PublicKey:
+		return anyKind[metadata.Type]("publickey", &metadata.PublicKey{})
+	case ast.Record:
+		return anyKind[metadata.Type]("record", &metadata.Record{})
+	}
+
+	primitive, ok := BasicToPrimitive[basic]
+	if !ok {
+		return metadata.Type{}, fmt.Errorf("unknown type %d", basic)
+	}
+
+	return anyKind[metadata.Type]("primitive", &metadata.Primitive{Value: primitive})
+}
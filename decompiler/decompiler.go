@@ -0,0 +1,281 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Package decompiler turns collection metadata back into a program, so that
+// the Polylang code of a stored collection can be recovered.
+package decompiler
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/durudex/go-polylang"
+	"github.com/durudex/go-polylang/ast"
+	"github.com/durudex/go-polylang/metadata"
+	"github.com/durudex/go-polylang/printer"
+
+	"github.com/alecthomas/participle/v2"
+)
+
+// body is the list of statements stored as the code of a method.
+type body struct {
+	Statements []*ast.Statement `parser:"@@*"`
+}
+
+var code = participle.MustBuild[body](
+	participle.Lexer(polylang.Lexer),
+)
+
+// Fprint decompiles the metadata and prints it as canonical Polylang code.
+func Fprint(w io.Writer, root metadata.Root) error {
+	prog, err := Decompile(root)
+	if err != nil {
+		return err
+	}
+
+	return printer.Fprint(w, prog)
+}
+
+// Decompile returns a program with a collection for every collection node of
+// the metadata. Namespaces are not part of the code and are dropped.
+func Decompile(root metadata.Root) (*ast.Program, error) {
+	prog := &ast.Program{}
+
+	for _, node := range root {
+		coll, ok, err := node.Collection()
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			return nil, fmt.Errorf("unknown node kind %q", node.Kind)
+		}
+
+		out, err := Collection(coll)
+		if err != nil {
+			return nil, err
+		}
+
+		prog.Nodes = append(prog.Nodes, &ast.Node{Collection: out})
+	}
+
+	return prog, nil
+}
+
+// Collection decompiles a single collection.
+func Collection(coll *metadata.Collection) (*ast.Collection, error) {
+	out := &ast.Collection{Name: coll.Name}
+
+	for _, attr := range coll.Attributes {
+		if directive, ok, err := attr.Directive(); ok {
+			if err != nil {
+				return nil, fmt.Errorf("collection %s: %w", coll.Name, err)
+			}
+
+			decorator, err := Directive(directive)
+			if err != nil {
+				return nil, fmt.Errorf("collection %s: %w", coll.Name, err)
+			}
+
+			out.Decorators = append(out.Decorators, decorator)
+
+			continue
+		}
+
+		item, err := collectionAttribute(attr)
+		if err != nil {
+			return nil, fmt.Errorf("collection %s: %w", coll.Name, err)
+		}
+
+		out.Items = append(out.Items, item)
+	}
+
+	return out, nil
+}
+
+func collectionAttribute(attr metadata.CollectionAttribute) (*ast.Item, error) {
+	switch attr.Kind {
+	case "property":
+		prop, _, err := attr.Property()
+		if err != nil {
+			return nil, err
+		}
+
+		return Property(prop)
+	case "index":
+		index, _, err := attr.Index()
+		if err != nil {
+			return nil, err
+		}
+
+		out, err := Index(index)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ast.Item{Index: out}, nil
+	case "method":
+		method, _, err := attr.Method()
+		if err != nil {
+			return nil, err
+		}
+
+		return Method(method)
+	default:
+		return nil, fmt.Errorf("unknown attribute kind %q", attr.Kind)
+	}
+}
+
+// Property decompiles a property into a field with its directives placed
+// before it.
+func Property(prop *metadata.Property) (*ast.Item, error) {
+	typ, err := Type(prop.Type)
+	if err != nil {
+		return nil, fmt.Errorf("property %s: %w", prop.Name, err)
+	}
+
+	out := &ast.Item{
+		Field: &ast.Field{Name: prop.Name, Optional: !prop.Required, Type: typ},
+	}
+
+	for i := range prop.Directives {
+		decorator, err := Directive(&prop.Directives[i])
+		if err != nil {
+			return nil, fmt.Errorf("property %s: %w", prop.Name, err)
+		}
+
+		out.Decorators = append(out.Decorators, decorator)
+	}
+
+	return out, nil
+}
+
+func Index(index *metadata.Index) (*ast.Index, error) {
+	out := &ast.Index{}
+
+	for _, field := range index.Fields {
+		order, ok := ast.StringToOrder[string(field.Direction)]
+		if !ok {
+			return nil, fmt.Errorf("index: unknown direction %q", field.Direction)
+		}
+
+		out.Fields = append(out.Fields, &ast.IndexField{
+			Name:  ast.FieldPath(strings.Join(field.FieldPath, ".")),
+			Order: order,
+		})
+	}
+
+	return out, nil
+}
+
+// Directive decompiles a directive, whose arguments must be references to
+// fields.
+func Directive(directive *metadata.Directive) (*ast.Decorator, error) {
+	name, ok := ast.StringToDecoratorName[directive.Name]
+	if !ok {
+		return nil, fmt.Errorf("unknown directive %q", directive.Name)
+	}
+
+	out := &ast.Decorator{Name: name}
+
+	for _, arg := range directive.Arguments {
+		ref, ok, err := arg.FieldReference()
+		if err != nil {
+			return nil, fmt.Errorf("directive %s: %w", directive.Name, err)
+		}
+
+		if !ok {
+			return nil, fmt.Errorf("directive %s: unknown argument kind %q", directive.Name, arg.Kind)
+		}
+
+		out.Arguments = append(out.Arguments, ast.FieldPath(strings.Join(ref.Path, ".")))
+	}
+
+	return out, nil
+}
+
+// Method decompiles a method into a function with its directives placed before
+// it. The code of the method is parsed back into statements.
+func Method(method *metadata.Method) (*ast.Item, error) {
+	fn := &ast.Function{Name: method.Name}
+	out := &ast.Item{Function: fn}
+
+	for _, attr := range method.Attributes {
+		if err := methodAttribute(out, attr); err != nil {
+			return nil, fmt.Errorf("method %s: %w", method.Name, err)
+		}
+	}
+
+	statements, err := Code(method.Code)
+	if err != nil {
+		return nil, fmt.Errorf("method %s: %w", method.Name, err)
+	}
+
+	fn.Statements = statements
+
+	return out, nil
+}
+
+func methodAttribute(item *ast.Item, attr metadata.MethodAttribute) error {
+	switch attr.Kind {
+	case "directive":
+		directive, _, err := attr.Directive()
+		if err != nil {
+			return err
+		}
+
+		decorator, err := Directive(directive)
+		if err != nil {
+			return err
+		}
+
+		item.Decorators = append(item.Decorators, decorator)
+	case "parameter":
+		param, _, err := attr.Parameter()
+		if err != nil {
+			return err
+		}
+
+		typ, err := Type(param.Type)
+		if err != nil {
+			return fmt.Errorf("parameter %s: %w", param.Name, err)
+		}
+
+		item.Function.Parameters = append(item.Function.Parameters, &ast.Field{
+			Name:     param.Name,
+			Optional: !param.Required,
+			Type:     typ,
+		})
+	case "returnvalue":
+		ret, _, err := attr.ReturnValue()
+		if err != nil {
+			return err
+		}
+
+		typ, err := Type(ret.Type)
+		if err != nil {
+			return fmt.Errorf("return type: %w", err)
+		}
+
+		item.Function.ReturnType = typ
+	default:
+		return fmt.Errorf("unknown attribute kind %q", attr.Kind)
+	}
+
+	return nil
+}
+
+// Code parses the code of a method into its statements.
+func Code(src string) ([]*ast.Statement, error) {
+	out, err := code.ParseString("", src)
+	if err != nil {
+		return nil, fmt.Errorf("code: %w", err)
+	}
+
+	return out.Statements, nil
+}
@@ -0,0 +1,223 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package decompiler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/durudex/go-polylang/compiler"
+	"github.com/durudex/go-polylang/decompiler"
+	"github.com/durudex/go-polylang/internal/jsontest"
+	"github.com/durudex/go-polylang/metadata"
+	"github.com/durudex/go-polylang/parser"
+)
+
+func TestFprint(t *testing.T) {
+	root, err := metadata.ParseFile("../metadata/fixtures/collection.json")
+	if err != nil {
+		t.Fatal("error: parsing metadata: ", err)
+	}
+
+	var got bytes.Buffer
+	if err := decompiler.Fprint(&got, root); err != nil {
+		t.Fatal("error: decompiling metadata: ", err)
+	}
+
+	want, err := os.ReadFile("fixtures/users.polylang")
+	if err != nil {
+		t.Fatal("error: reading fixtures file: ", err)
+	}
+
+	if got.String() != string(want) {
+		t.Fatalf("error: code does not match:\n%s", got.String())
+	}
+}
+
+var FprintTests = map[string]struct {
+	data string
+	want string
+}{
+	"Types": {
+		data: `[{"kind":"collection","namespace":{"kind":"namespace","value":"ns"},"name":"T","attributes":[` +
+			`{"kind":"property","name":"a","type":{"kind":"array","value":{"kind":"primitive","value":"string"}},"directives":[],"required":true},` +
+			`{"kind":"property","name":"b","type":{"kind":"map","key":{"kind":"primitive","value":"string"},"value":{"kind":"foreignrecord","collection":"T"}},"directives":[],"required":false},` +
+			`{"kind":"property","name":"c","type":{"kind":"object","fields":[{"name":"d","type":{"kind":"primitive","value":"bytes"},"required":false}]},"directives":[],"required":true},` +
+			`{"kind":"property","name":"e","type":{"kind":"record"},"directives":[],"required":true}]}]`,
+		want: "collection T {\n" +
+			"    a: string[];\n" +
+			"    b?: map<string, T>;\n" +
+			"    c: {\n" +
+			"        d?: bytes;\n" +
+			"    };\n" +
+			"    e: record;\n" +
+			"}\n",
+	},
+	"Directives": {
+		data: `[{"kind":"collection","namespace":{"kind":"namespace","value":"ns"},"name":"T","attributes":[` +
+			`{"kind":"directive","name":"read","arguments":[]},` +
+			`{"kind":"property","name":"owner","type":{"kind":"publickey"},"directives":[{"name":"delegate","arguments":[]}],"required":true},` +
+			`{"kind":"index","fields":[{"direction":"desc","fieldPath":["owner"]},{"direction":"asc","fieldPath":["info","name"]}]},` +
+			`{"kind":"method","name":"f","attributes":[` +
+			`{"kind":"directive","name":"call","arguments":[{"kind":"fieldreference","path":["owner"]}]},` +
+			`{"kind":"parameter","name":"a","type":{"kind":"primitive","value":"number"},"required":true},` +
+			`{"kind":"returnvalue","name":"_","type":{"kind":"primitive","value":"boolean"}}],` +
+			`"code":"while (a > 0) { a -= 1; } return true;"}]}]`,
+		want: "@read\n" +
+			"collection T {\n" +
+			"    @delegate\n" +
+			"    owner: PublicKey;\n" +
+			"\n" +
+			"    @index([owner, desc], info.name);\n" +
+			"\n" +
+			"    @call(owner)\n" +
+			"    function f(a: number): boolean {\n" +
+			"        while (a > 0) {\n" +
+			"            a -= 1;\n" +
+			"        }\n" +
+			"        return true;\n" +
+			"    }\n" +
+			"}\n",
+	},
+}
+
+func TestFprint_Code(t *testing.T) {
+	for name, test := range FprintTests {
+		t.Run(name, func(t *testing.T) {
+			root, err := metadata.Parse([]byte(test.data))
+			if err != nil {
+				t.Fatal("error: parsing metadata: ", err)
+			}
+
+			var got bytes.Buffer
+			if err := decompiler.Fprint(&got, root); err != nil {
+				t.Fatal("error: decompiling metadata: ", err)
+			}
+
+			if got.String() != test.want {
+				t.Fatalf("error: code does not match:\n%s", got.String())
+			}
+		})
+	}
+}
+
+var DecompileErrorTests = map[string]string{
+	"Node":      `[{"kind":"function"}]`,
+	"Attribute": `[{"kind":"collection","namespace":{"kind":"namespace","value":"ns"},"name":"T","attributes":[{"kind":"unknown"}]}]`,
+	"Directive": `[{"kind":"collection","namespace":{"kind":"namespace","value":"ns"},"name":"T","attributes":[{"kind":"directive","name":"unknown","arguments":[]}]}]`,
+	"Array": `[{"kind":"collection","namespace":{"kind":"namespace","value":"ns"},"name":"T","attributes":[` +
+		`{"kind":"property","name":"a","type":{"kind":"array","value":{"kind":"object","fields":[]}},"directives":[],"required":true}]}]`,
+	"Code": `[{"kind":"collection","namespace":{"kind":"namespace","value":"ns"},"name":"T","attributes":[` +
+		`{"kind":"method","name":"f","attributes":[],"code":"return"}]}]`,
+}
+
+func TestDecompile_Error(t *testing.T) {
+	for name, data := range DecompileErrorTests {
+		t.Run(name, func(t *testing.T) {
+			root, err := metadata.Parse([]byte(data))
+			if err != nil {
+				t.Fatal("error: parsing metadata: ", err)
+			}
+
+			if _, err := decompiler.Decompile(root); err == nil {
+				t.Fatal("error: expected decompiling error")
+			}
+		})
+	}
+}
+
+func TestDecompile_RoundTrip(t *testing.T) {
+	want, err := os.ReadFile("../metadata/fixtures/collection.json")
+	if err != nil {
+		t.Fatal("error: reading fixtures file: ", err)
+	}
+
+	root, err := metadata.Parse(want)
+	if err != nil {
+		t.Fatal("error: parsing metadata: ", err)
+	}
+
+	var code bytes.Buffer
+	if err := decompiler.Fprint(&code, root); err != nil {
+		t.Fatal("error: decompiling metadata: ", err)
+	}
+
+	prog, err := parser.Must.ParseBytes("", code.Bytes())
+	if err != nil {
+		t.Fatal("error: parsing polylang code: ", err)
+	}
+
+	compiled, err := compiler.Compile(prog, "Program")
+	if err != nil {
+		t.Fatal("error: compiling program: ", err)
+	}
+
+	got, err := json.Marshal(compiled)
+	if err != nil {
+		t.Fatal("error: marshal json: ", err)
+	}
+
+	var g, w any
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatal("error: unmarshal json: ", err)
+	}
+
+	if err := json.Unmarshal(want, &w); err != nil {
+		t.Fatal("error: unmarshal json: ", err)
+	}
+
+	if !reflect.DeepEqual(g, jsontest.RenameReturnValues(w)) {
+		t.Fatalf("error: metadata does not match:\n%s", got)
+	}
+}
+
+var RoundTripTests = map[string]string{
+	"FieldReference": `[{"kind":"collection","namespace":{"kind":"namespace","value":"ns"},"name":"T","attributes":[` +
+		`{"kind":"property","name":"info","type":{"kind":"object","fields":[{"name":"owner","type":{"kind":"publickey"},"required":true}]},"directives":[],"required":true},` +
+		`{"kind":"method","name":"f","attributes":[` +
+		`{"kind":"directive","name":"call","arguments":[{"kind":"fieldreference","path":["info","owner"]}]}],` +
+		`"code":"this.info = info;"}]}]`,
+}
+
+func TestDecompile_RoundTrip_Code(t *testing.T) {
+	for name, data := range RoundTripTests {
+		t.Run(name, func(t *testing.T) {
+			root, err := metadata.Parse([]byte(data))
+			if err != nil {
+				t.Fatal("error: parsing metadata: ", err)
+			}
+
+			var code bytes.Buffer
+			if err := decompiler.Fprint(&code, root); err != nil {
+				t.Fatal("error: decompiling metadata: ", err)
+			}
+
+			prog, err := parser.Must.ParseBytes("", code.Bytes())
+			if err != nil {
+				t.Fatal("error: parsing polylang code: ", err)
+			}
+
+			compiled, err := compiler.Compile(prog, "ns")
+			if err != nil {
+				t.Fatal("error: compiling program: ", err)
+			}
+
+			got, err := json.Marshal(compiled)
+			if err != nil {
+				t.Fatal("error: marshal json: ", err)
+			}
+
+			if !reflect.DeepEqual(jsontest.Normalize(t, got), jsontest.Normalize(t, []byte(data))) {
+				t.Fatalf("error: metadata does not match:\n%s", got)
+			}
+		})
+	}
+}
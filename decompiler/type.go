@@ -0,0 +1,119 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package decompiler
+
+import (
+	"fmt"
+
+	"github.com/durudex/go-polylang/ast"
+	"github.com/durudex/go-polylang/metadata"
+)
+
+var PrimitiveToBasic = map[metadata.PrimitiveType]ast.BasicType{
+	metadata.PrimitiveTypeString:  ast.String,
+	metadata.PrimitiveTypeNumber:  ast.Number,
+	metadata.PrimitiveTypeBoolean: ast.Boolean,
+	metadata.PrimitiveTypeBytes:   ast.Bytes,
+}
+
+// Type decompiles a type. Arrays can only be written with basic element types,
+// so any other array is an error.
+func Type(t metadata.Type) (ast.Type, error) {
+	switch t.Kind {
+	case "primitive", "publickey", "record":
+		basic, err := basicType(t)
+		if err != nil {
+			return ast.Type{}, err
+		}
+
+		return ast.Type{Basic: basic}, nil
+	case "array":
+		array, _, err := t.Array()
+		if err != nil {
+			return ast.Type{}, err
+		}
+
+		elem, err := basicType(array.Value)
+		if err != nil {
+			return ast.Type{}, fmt.Errorf("array: %w", err)
+		}
+
+		return ast.Type{Basic: elem, Array: true}, nil
+	case "map":
+		mp, _, err := t.Map()
+		if err != nil {
+			return ast.Type{}, err
+		}
+
+		key, err := basicType(mp.Key)
+		if err != nil {
+			return ast.Type{}, fmt.Errorf("map key: %w", err)
+		}
+
+		value, err := Type(mp.Value)
+		if err != nil {
+			return ast.Type{}, fmt.Errorf("map value: %w", err)
+		}
+
+		return ast.Type{Map: &ast.Map{Key: key, Value: value}}, nil
+	case "object":
+		obj, _, err := t.Object()
+		if err != nil {
+			return ast.Type{}, err
+		}
+
+		out := ast.Type{Object: []*ast.Field{}}
+
+		for _, field := range obj.Fields {
+			typ, err := Type(field.Type)
+			if err != nil {
+				return ast.Type{}, fmt.Errorf("field %s: %w", field.Name, err)
+			}
+
+			out.Object = append(out.Object, &ast.Field{
+				Name:     field.Name,
+				Optional: !field.Required,
+				Type:     typ,
+			})
+		}
+
+		return out, nil
+	case "foreignrecord":
+		rec, _, err := t.ForeignRecord()
+		if err != nil {
+			return ast.Type{}, err
+		}
+
+		return ast.Type{Foreign: rec.Collection}, nil
+	default:
+		return ast.Type{}, fmt.Errorf("unknown type kind %q", t.Kind)
+	}
+}
+
+func basicType(t metadata.Type) (ast.BasicType, error) {
+	switch t.Kind {
+	case "publickey":
+		return ast.PublicKey, nil
+	case "record":
+		return ast.Record, nil
+	case "primitive":
+		primitive, _, err := t.Primitive()
+		if err != nil {
+			return 0, err
+		}
+
+		basic, ok := PrimitiveToBasic[primitive.Value]
+		if !ok {
+			return 0, fmt.Errorf("unknown primitive type %q", primitive.Value)
+		}
+
+		return basic, nil
+	default:
+		return 0, fmt.Errorf("%s is not a basic type", t.Kind)
+	}
+}
@@ -0,0 +1,367 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Package diff compares two versions of a collection and reports whether the
+// changes between them are compatible with existing records and callers.
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/durudex/go-polylang/ast"
+	"github.com/durudex/go-polylang/compiler"
+	"github.com/durudex/go-polylang/metadata"
+)
+
+type Kind int
+
+const (
+	AddedField Kind = iota + 1
+	RemovedField
+	ChangedFieldType
+	RequiredToOptional
+	OptionalToRequired
+	AddedIndex
+	RemovedIndex
+	AddedMethod
+	RemovedMethod
+	ChangedMethod
+)
+
+var KindToString = map[Kind]string{
+	AddedField:         "added-field",
+	RemovedField:       "removed-field",
+	ChangedFieldType:   "changed-field-type",
+	RequiredToOptional: "required-to-optional",
+	OptionalToRequired: "optional-to-required",
+	AddedIndex:         "added-index",
+	RemovedIndex:       "removed-index",
+	AddedMethod:        "added-method",
+	RemovedMethod:      "removed-method",
+	ChangedMethod:      "changed-method",
+}
+
+func (k Kind) String() string { return KindToString[k] }
+
+// Change is a single difference between two versions of a collection. Path is
+// the dotted path of a field, the name of a method or the fields of an index.
+// A breaking change may make existing records or calls invalid.
+type Change struct {
+	Kind     Kind
+	Path     string
+	Message  string
+	Breaking bool
+}
+
+func (c *Change) String() string {
+	compatibility := "compatible"
+	if c.Breaking {
+		compatibility = "breaking"
+	}
+
+	return fmt.Sprintf("%s: %s: %s (%s)", compatibility, c.Path, c.Message, c.Kind)
+}
+
+// Changes is the list of differences between two versions of a collection,
+// with fields first, then indexes and methods.
+type Changes []*Change
+
+func (c Changes) String() string {
+	lines := make([]string, len(c))
+
+	for i, change := range c {
+		lines[i] = change.String()
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Breaking reports whether any of the changes is breaking.
+func (c Changes) Breaking() bool {
+	for _, change := range c {
+		if change.Breaking {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Compare returns the changes from one version of a collection to another,
+// each given as an *ast.Collection or a *metadata.Collection.
+func Compare(from, to any) (Changes, error) {
+	a, err := single(from)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := single(to)
+	if err != nil {
+		return nil, err
+	}
+
+	return Collection(a, b)
+}
+
+func single(node any) (*metadata.Collection, error) {
+	switch node.(type) {
+	case *metadata.Collection, *ast.Collection:
+	default:
+		return nil, fmt.Errorf("unsupported node type %T", node)
+	}
+
+	colls, err := compiler.Collections(node)
+	if err != nil {
+		return nil, err
+	}
+
+	return colls[0], nil
+}
+
+// Collection returns the changes from one version of a collection to another.
+func Collection(from, to *metadata.Collection) (Changes, error) {
+	a, err := attributes(from)
+	if err != nil {
+		return nil, fmt.Errorf("collection %s: %w", from.Name, err)
+	}
+
+	b, err := attributes(to)
+	if err != nil {
+		return nil, fmt.Errorf("collection %s: %w", to.Name, err)
+	}
+
+	d := &differ{}
+
+	if err := d.fields("", a.fields, b.fields); err != nil {
+		return nil, err
+	}
+
+	d.indexes(a.indexes, b.indexes)
+
+	if err := d.methods(a.methods, b.methods); err != nil {
+		return nil, err
+	}
+
+	return d.changes, nil
+}
+
+// collection holds the attributes of a collection that are compared.
+type collection struct {
+	fields  []metadata.ObjectField
+	indexes []string
+	methods []*metadata.Method
+}
+
+func attributes(coll *metadata.Collection) (*collection, error) {
+	out := &collection{}
+
+	for _, attr := range coll.Attributes {
+		switch attr.Kind {
+		case "property":
+			prop, _, err := attr.Property()
+			if err != nil {
+				return nil, err
+			}
+
+			out.fields = append(out.fields, metadata.ObjectField{
+				Name: prop.Name, Type: prop.Type, Required: prop.Required,
+			})
+		case "index":
+			index, _, err := attr.Index()
+			if err != nil {
+				return nil, err
+			}
+
+			out.indexes = append(out.indexes, indexString(index))
+		case "method":
+			method, _, err := attr.Method()
+			if err != nil {
+				return nil, err
+			}
+
+			out.methods = append(out.methods, method)
+		}
+	}
+
+	return out, nil
+}
+
+type differ struct {
+	changes Changes
+}
+
+func (d *differ) add(kind Kind, path string, breaking bool, format string, args ...any) {
+	d.changes = append(d.changes, &Change{
+		Kind:     kind,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+		Breaking: breaking,
+	})
+}
+
+// fields compares the fields of a collection or of an object type, whose path
+// is prefix.
+func (d *differ) fields(prefix string, from, to []metadata.ObjectField) error {
+	for _, a := range from {
+		path := prefix + a.Name
+
+		b, ok := field(to, a.Name)
+		if !ok {
+			d.add(RemovedField, path, true, "field was removed")
+
+			continue
+		}
+
+		if err := d.typ(path, a.Type, b.Type); err != nil {
+			return err
+		}
+
+		switch {
+		case a.Required && !b.Required:
+			d.add(RequiredToOptional, path, false, "field became optional")
+		case !a.Required && b.Required:
+			d.add(OptionalToRequired, path, true, "field became required")
+		}
+	}
+
+	for _, b := range to {
+		if _, ok := field(from, b.Name); ok {
+			continue
+		}
+
+		if b.Required {
+			d.add(AddedField, prefix+b.Name, true, "required field was added")
+		} else {
+			d.add(AddedField, prefix+b.Name, false, "optional field was added")
+		}
+	}
+
+	return nil
+}
+
+// typ compares the types of the value at the path. Objects that remain
+// objects are compared field by field, also as the elements of arrays and the
+// values of maps, whose paths end with [] and with the key type in brackets.
+func (d *differ) typ(path string, a, b metadata.Type) error {
+	switch {
+	case a.Kind == "object" && b.Kind == "object":
+		objA, _, err := a.Object()
+		if err != nil {
+			return err
+		}
+
+		objB, _, err := b.Object()
+		if err != nil {
+			return err
+		}
+
+		return d.fields(path+".", objA.Fields, objB.Fields)
+	case a.Kind == "array" && b.Kind == "array":
+		arrA, _, err := a.Array()
+		if err != nil {
+			return err
+		}
+
+		arrB, _, err := b.Array()
+		if err != nil {
+			return err
+		}
+
+		return d.typ(path+"[]", arrA.Value, arrB.Value)
+	case a.Kind == "map" && b.Kind == "map":
+		mapA, _, err := a.Map()
+		if err != nil {
+			return err
+		}
+
+		mapB, _, err := b.Map()
+		if err != nil {
+			return err
+		}
+
+		keyA, err := typeString(mapA.Key)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", path, err)
+		}
+
+		keyB, err := typeString(mapB.Key)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", path, err)
+		}
+
+		if keyA == keyB {
+			return d.typ(path+"["+keyA+"]", mapA.Value, mapB.Value)
+		}
+	}
+
+	typA, err := typeString(a)
+	if err != nil {
+		return fmt.Errorf("field %s: %w", path, err)
+	}
+
+	typB, err := typeString(b)
+	if err != nil {
+		return fmt.Errorf("field %s: %w", path, err)
+	}
+
+	if typA != typB {
+		d.add(ChangedFieldType, path, true, "type changed from %s to %s", typA, typB)
+	}
+
+	return nil
+}
+
+func field(fields []metadata.ObjectField, name string) (metadata.ObjectField, bool) {
+	for _, field := range fields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+
+	return metadata.ObjectField{}, false
+}
+
+// indexes compares indexes by their fields. Queries may depend on an index,
+// so removing one is breaking.
+func (d *differ) indexes(from, to []string) {
+	for _, index := range from {
+		if !contains(to, index) {
+			d.add(RemovedIndex, index, true, "index was removed")
+		}
+	}
+
+	for _, index := range to {
+		if !contains(from, index) {
+			d.add(AddedIndex, index, false, "index was added")
+		}
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+func indexString(index *metadata.Index) string {
+	fields := make([]string, len(index.Fields))
+
+	for i, field := range index.Fields {
+		fields[i] = strings.Join(field.FieldPath, ".")
+
+		if field.Direction == "desc" {
+			fields[i] = "[" + fields[i] + ", desc]"
+		}
+	}
+
+	return strings.Join(fields, ", ")
+}
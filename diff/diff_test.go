@@ -0,0 +1,187 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package diff_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/durudex/go-polylang/compiler"
+	"github.com/durudex/go-polylang/diff"
+	"github.com/durudex/go-polylang/metadata"
+	"github.com/durudex/go-polylang/parser"
+)
+
+var CompareTests = map[string]struct {
+	from string
+	to   string
+	want []string
+}{
+	"Same": {
+		from: "collection T { id: string; @index(id); function f(a: string) { this.id = a; } }",
+		to:   "collection T { id: string; @index(id); function f(a: string) { this.id = a + a; } }",
+	},
+	"Fields": {
+		from: "collection T { a: string; b: number; c?: string; d: string; }",
+		to:   "collection T { a: number; b?: number; c: string; e?: string; f: boolean; }",
+		want: []string{
+			"breaking: a: type changed from string to number (changed-field-type)",
+			"compatible: b: field became optional (required-to-optional)",
+			"breaking: c: field became required (optional-to-required)",
+			"breaking: d: field was removed (removed-field)",
+			"compatible: e: optional field was added (added-field)",
+			"breaking: f: required field was added (added-field)",
+		},
+	},
+	"Nested": {
+		from: "collection T { a: { b: string; c: { d: number; }; }; e: { f: string; }; }",
+		to:   "collection T { a: { b?: string; c: { d: string; }; g?: boolean; }; e: map<string, string>; }",
+		want: []string{
+			"compatible: a.b: field became optional (required-to-optional)",
+			"breaking: a.c.d: type changed from number to string (changed-field-type)",
+			"compatible: a.g: optional field was added (added-field)",
+			"breaking: e: type changed from { f: string; } to map<string, string> (changed-field-type)",
+		},
+	},
+	"Collections": {
+		from: "collection T { c: map<string, { d: number; }>; e: map<string, number[]>; f: map<string, string>; }",
+		to:   "collection T { c: map<string, { d: string; }>; e: map<string, string[]>; f: map<number, string>; }",
+		want: []string{
+			"breaking: c[string].d: type changed from number to string (changed-field-type)",
+			"breaking: e[string][]: type changed from number to string (changed-field-type)",
+			"breaking: f: type changed from map<string, string> to map<number, string> (changed-field-type)",
+		},
+	},
+	"Indexes": {
+		from: "collection T { a: string; b: number; @index(a); @index(a, [b, desc]); }",
+		to:   "collection T { a: string; b: number; @index(a, [b, desc]); @index(b); }",
+		want: []string{
+			"breaking: a: index was removed (removed-index)",
+			"compatible: b: index was added (added-index)",
+		},
+	},
+	"Methods": {
+		from: "collection T { function a(x: string) {} function b(x: string): number { return 1; } " +
+			"function c(x: string) {} function d(x?: string) {} function e() {} }",
+		to: "collection T { function a(y: string, z?: number) {} function b(x: string): string { return ''; } " +
+			"function c(x: string, y: number) {} function d(x: string) {} function f() {} }",
+		want: []string{
+			"compatible: a: signature changed from (x: string) to (y: string, z?: number) (changed-method)",
+			"breaking: b: signature changed from (x: string): number to (x: string): string (changed-method)",
+			"breaking: c: signature changed from (x: string) to (x: string, y: number) (changed-method)",
+			"breaking: d: signature changed from (x?: string) to (x: string) (changed-method)",
+			"breaking: e: method was removed (removed-method)",
+			"compatible: f: method was added (added-method)",
+		},
+	},
+}
+
+func TestCompare(t *testing.T) {
+	for name, test := range CompareTests {
+		t.Run(name, func(t *testing.T) {
+			from, err := parser.Must.ParseString("", test.from)
+			if err != nil {
+				t.Fatal("error: parsing polylang code: ", err)
+			}
+
+			to, err := parser.Must.ParseString("", test.to)
+			if err != nil {
+				t.Fatal("error: parsing polylang code: ", err)
+			}
+
+			changes, err := diff.Compare(from.Nodes[0].Collection, to.Nodes[0].Collection)
+			if err != nil {
+				t.Fatal("error: comparing collections: ", err)
+			}
+
+			var got []string
+			for _, change := range changes {
+				got = append(got, change.String())
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("error: changes do not match:\n%s", changes)
+			}
+		})
+	}
+}
+
+func TestCompare_Array(t *testing.T) {
+	collection := func(required bool) string {
+		return fmt.Sprintf(`[{"kind":"collection","namespace":{"kind":"namespace","value":"ns"},"name":"T","attributes":[`+
+			`{"kind":"property","name":"a","type":{"kind":"array","value":{"kind":"object","fields":[`+
+			`{"name":"b","type":{"kind":"primitive","value":"string"},"required":%t}]}},"directives":[],"required":true}]}]`, required)
+	}
+
+	from, err := metadata.Parse([]byte(collection(true)))
+	if err != nil {
+		t.Fatal("error: parsing metadata: ", err)
+	}
+
+	to, err := metadata.Parse([]byte(collection(false)))
+	if err != nil {
+		t.Fatal("error: parsing metadata: ", err)
+	}
+
+	a, _, err := from[0].Collection()
+	if err != nil {
+		t.Fatal("error: node is not collection: ", err)
+	}
+
+	b, _, err := to[0].Collection()
+	if err != nil {
+		t.Fatal("error: node is not collection: ", err)
+	}
+
+	changes, err := diff.Compare(a, b)
+	if err != nil {
+		t.Fatal("error: comparing collections: ", err)
+	}
+
+	if want := "compatible: a[].b: field became optional (required-to-optional)"; len(changes) != 1 || changes[0].String() != want {
+		t.Fatalf("error: changes do not match:\n%s", changes)
+	}
+}
+
+func TestCompare_Metadata(t *testing.T) {
+	prog, err := parser.Parse("../compiler/fixtures/users.polylang")
+	if err != nil {
+		t.Fatal("error: parsing polylang code: ", err)
+	}
+
+	coll, err := compiler.Collection(prog.Nodes[0].Collection, "ns")
+	if err != nil {
+		t.Fatal("error: compiling collection: ", err)
+	}
+
+	changes, err := diff.Compare(prog.Nodes[0].Collection, coll)
+	if err != nil {
+		t.Fatal("error: comparing collections: ", err)
+	}
+
+	if len(changes) != 0 {
+		t.Fatalf("error: unexpected changes:\n%s", changes)
+	}
+
+	if _, err := diff.Compare(prog, coll); err == nil {
+		t.Fatal("error: expected an error for an unsupported node")
+	}
+}
+
+func TestChanges_Breaking(t *testing.T) {
+	changes := diff.Changes{{Kind: diff.AddedIndex}}
+	if changes.Breaking() {
+		t.Fatal("error: compatible changes are breaking")
+	}
+
+	changes = append(changes, &diff.Change{Kind: diff.RemovedField, Breaking: true})
+	if !changes.Breaking() {
+		t.Fatal("error: breaking changes are compatible")
+	}
+}
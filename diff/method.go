@@ -0,0 +1,185 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/durudex/go-polylang/metadata"
+)
+
+// signature is the part of a method that callers depend on.
+type signature struct {
+	params  []metadata.Parameter
+	returns *metadata.Type
+}
+
+func methodSignature(method *metadata.Method) (*signature, error) {
+	out := &signature{}
+
+	for _, attr := range method.Attributes {
+		switch attr.Kind {
+		case "parameter":
+			param, _, err := attr.Parameter()
+			if err != nil {
+				return nil, err
+			}
+
+			out.params = append(out.params, *param)
+		case "returnvalue":
+			value, _, err := attr.ReturnValue()
+			if err != nil {
+				return nil, err
+			}
+
+			out.returns = &value.Type
+		}
+	}
+
+	return out, nil
+}
+
+func (s *signature) String() string {
+	params := make([]string, len(s.params))
+
+	for i, param := range s.params {
+		params[i] = param.Name
+		if !param.Required {
+			params[i] += "?"
+		}
+
+		// The types were already printed when the signatures were compared.
+		typ, _ := typeString(param.Type)
+		params[i] += ": " + typ
+	}
+
+	out := "(" + strings.Join(params, ", ") + ")"
+
+	if s.returns != nil {
+		typ, _ := typeString(*s.returns)
+		out += ": " + typ
+	}
+
+	return out
+}
+
+// compatible reports whether every call of the method with the from signature
+// is still valid with the to signature, and returns the same kind of value.
+// Parameters are positional, so their names do not matter.
+func compatible(from, to *signature) (bool, error) {
+	if len(to.params) < len(from.params) {
+		return false, nil
+	}
+
+	for i, b := range to.params {
+		if i >= len(from.params) {
+			if b.Required {
+				return false, nil
+			}
+
+			continue
+		}
+
+		a := from.params[i]
+
+		if b.Required && !a.Required {
+			return false, nil
+		}
+
+		if ok, err := sameType(a.Type, b.Type); err != nil || !ok {
+			return false, err
+		}
+	}
+
+	switch {
+	case from.returns == nil:
+		return true, nil
+	case to.returns == nil:
+		return false, nil
+	default:
+		return sameType(*from.returns, *to.returns)
+	}
+}
+
+// equal reports whether two signatures are the same.
+func equal(from, to *signature) (bool, error) {
+	if len(from.params) != len(to.params) || (from.returns == nil) != (to.returns == nil) {
+		return false, nil
+	}
+
+	for i := range from.params {
+		a, b := from.params[i], to.params[i]
+
+		if a.Name != b.Name || a.Required != b.Required {
+			return false, nil
+		}
+
+		if ok, err := sameType(a.Type, b.Type); err != nil || !ok {
+			return false, err
+		}
+	}
+
+	if from.returns == nil {
+		return true, nil
+	}
+
+	return sameType(*from.returns, *to.returns)
+}
+
+func (d *differ) methods(from, to []*metadata.Method) error {
+	for _, a := range from {
+		b := method(to, a.Name)
+		if b == nil {
+			d.add(RemovedMethod, a.Name, true, "method was removed")
+
+			continue
+		}
+
+		sigA, err := methodSignature(a)
+		if err != nil {
+			return fmt.Errorf("method %s: %w", a.Name, err)
+		}
+
+		sigB, err := methodSignature(b)
+		if err != nil {
+			return fmt.Errorf("method %s: %w", b.Name, err)
+		}
+
+		if ok, err := equal(sigA, sigB); err != nil {
+			return fmt.Errorf("method %s: %w", a.Name, err)
+		} else if ok {
+			continue
+		}
+
+		ok, err := compatible(sigA, sigB)
+		if err != nil {
+			return fmt.Errorf("method %s: %w", a.Name, err)
+		}
+
+		d.add(ChangedMethod, a.Name, !ok, "signature changed from %s to %s", sigA, sigB)
+	}
+
+	for _, b := range to {
+		if method(from, b.Name) == nil {
+			d.add(AddedMethod, b.Name, false, "method was added")
+		}
+	}
+
+	return nil
+}
+
+func method(methods []*metadata.Method, name string) *metadata.Method {
+	for _, method := range methods {
+		if method.Name == name {
+			return method
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/durudex/go-polylang/metadata"
+)
+
+func sameType(a, b metadata.Type) (bool, error) {
+	typA, err := typeString(a)
+	if err != nil {
+		return false, err
+	}
+
+	typB, err := typeString(b)
+	if err != nil {
+		return false, err
+	}
+
+	return typA == typB, nil
+}
+
+// typeString returns a type as it is written in Polylang, with any element
+// type for arrays, which is how types are compared.
+func typeString(t metadata.Type) (string, error) {
+	switch t.Kind {
+	case "primitive":
+		primitive, _, err := t.Primitive()
+		if err != nil {
+			return "", err
+		}
+
+		return string(primitive.Value), nil
+	case "publickey":
+		return "PublicKey", nil
+	case "record":
+		return "record", nil
+	case "foreignrecord":
+		rec, _, err := t.ForeignRecord()
+		if err != nil {
+			return "", err
+		}
+
+		return rec.Collection, nil
+	case "array":
+		array, _, err := t.Array()
+		if err != nil {
+			return "", err
+		}
+
+		elem, err := typeString(array.Value)
+		if err != nil {
+			return "", err
+		}
+
+		return elem + "[]", nil
+	case "map":
+		mp, _, err := t.Map()
+		if err != nil {
+			return "", err
+		}
+
+		key, err := typeString(mp.Key)
+		if err != nil {
+			return "", err
+		}
+
+		value, err := typeString(mp.Value)
+		if err != nil {
+			return "", err
+		}
+
+		return "map<" + key + ", " + value + ">", nil
+	case "object":
+		obj, _, err := t.Object()
+		if err != nil {
+			return "", err
+		}
+
+		if len(obj.Fields) == 0 {
+			return "{}", nil
+		}
+
+		fields := make([]string, len(obj.Fields))
+
+		for i, field := range obj.Fields {
+			typ, err := typeString(field.Type)
+			if err != nil {
+				return "", err
+			}
+
+			fields[i] = field.Name
+			if !field.Required {
+				fields[i] += "?"
+			}
+
+			fields[i] += ": " + typ + ";"
+		}
+
+		return "{ " + strings.Join(fields, " ") + " }", nil
+	default:
+		return "", fmt.Errorf("unknown type kind %q", t.Kind)
+	}
+}
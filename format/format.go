@@ -0,0 +1,49 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Package format implements standard formatting of Polylang source.
+package format
+
+import (
+	"bytes"
+
+	"github.com/durudex/go-polylang"
+	"github.com/durudex/go-polylang/parser"
+	"github.com/durudex/go-polylang/printer"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+// Source formats src in canonical style, keeping its comments. The filename
+// is only used in error messages.
+func Source(filename string, src []byte) ([]byte, error) {
+	prog, err := parser.Must.ParseBytes(filename, src)
+	if err != nil {
+		return nil, err
+	}
+
+	lex, err := polylang.CommentLexer.LexString(filename, string(src))
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := lexer.ConsumeAll(lex)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+
+	if err := printer.Fprint(&buf, &printer.CommentedProgram{
+		Program: prog,
+		Tokens:  tokens,
+	}); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
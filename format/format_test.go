@@ -0,0 +1,81 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package format_test
+
+import (
+	"testing"
+
+	"github.com/durudex/go-polylang/format"
+)
+
+var SourceTests = map[string]struct {
+	code string
+	want string
+}{
+	"OK": {
+		code: "collection Test{id:string;}",
+		want: "collection Test {\n    id: string;\n}\n",
+	},
+	"Line Comments": {
+		code: "// Test collection.\ncollection Test {\n  // The identifier.\n  id: string; // trailing\n" +
+			"  function f() {\n    // inside\n    return 1; // result\n    // last\n  }\n  // end\n}\n// eof\n",
+		want: "// Test collection.\ncollection Test {\n    // The identifier.\n    id: string; // trailing\n\n" +
+			"    function f() {\n        // inside\n        return 1; // result\n        // last\n    }\n" +
+			"    // end\n}\n// eof\n",
+	},
+	"Blank Lines": {
+		code: "// header\n\n\n// doc\ncollection Test {\n  id: string;\n\n\n  // name\n  name: string;\n}\n\n\n// eof\n",
+		want: "// header\n\n// doc\ncollection Test {\n    id: string;\n\n    // name\n    name: string;\n}\n\n// eof\n",
+	},
+	"Block Comments": {
+		code: "/* header */ collection Test { /* empty */ }",
+		want: "/* header */\ncollection Test {\n    /* empty */\n}\n",
+	},
+	"Object Comments": {
+		code: "collection Test { info: { a: string; // a\n b: string; // b\n }; }",
+		want: "collection Test {\n    info: {\n        a: string; // a\n        b: string; // b\n    };\n}\n",
+	},
+	"Comment Markers In Strings": {
+		code: "function f() { return '// not a comment'; }",
+		want: "function f() {\n    return '// not a comment';\n}\n",
+	},
+	"Only Comments": {
+		code: "// nothing here\n",
+		want: "// nothing here\n",
+	},
+}
+
+func TestSource(t *testing.T) {
+	for name, test := range SourceTests {
+		t.Run(name, func(t *testing.T) {
+			got, err := format.Source("", []byte(test.code))
+			if err != nil {
+				t.Fatal("error: formatting source: ", err)
+			}
+
+			if string(got) != test.want {
+				t.Fatalf("error: formatted source does not match:\n%s", got)
+			}
+
+			again, err := format.Source("", got)
+			if err != nil {
+				t.Fatal("error: formatting formatted source: ", err)
+			}
+
+			if string(again) != string(got) {
+				t.Fatalf("error: formatting is not idempotent:\n%s", again)
+			}
+		})
+	}
+}
+
+func TestSource_Error(t *testing.T) {
+	if _, err := format.Source("", []byte("collection {")); err == nil {
+		t.Fatal("error: expected parsing error")
+	}
+}
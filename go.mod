@@ -2,4 +2,7 @@ module github.com/durudex/go-polylang
 
 go 1.19
 
-require github.com/alecthomas/participle/v2 v2.0.0-beta.5
+require (
+	github.com/alecthomas/participle/v2 v2.0.0-beta.5
+	github.com/durudex/go-polylang/metadata v0.1.0
+)
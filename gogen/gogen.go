@@ -0,0 +1,339 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Package gogen generates Go types for the records of Polylang collections.
+package gogen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/durudex/go-polylang/compiler"
+	"github.com/durudex/go-polylang/metadata"
+)
+
+const DefaultPackage = "collections"
+
+// Config controls the output of Fprint.
+type Config struct {
+	// Package is the name of the generated package, DefaultPackage if empty.
+	Package string
+}
+
+// Fprint generates the code with the default configuration.
+func Fprint(w io.Writer, node any) error {
+	return (&Config{}).Fprint(w, node)
+}
+
+// Fprint generates a Go file with a struct for the records of every collection
+// of the node, which is any node accepted by compiler.Collections, and writes
+// it to w.
+//
+// Optional fields are pointers, except for slices and maps, which are left
+// out of JSON when nil. Inline objects become named structs, maps with number
+// keys are keyed by json.Number, and foreign records are typed references to
+// their collection. Types and fields whose Go names collide with earlier ones
+// are renamed with a trailing underscore, and collections are named first.
+func (c *Config) Fprint(w io.Writer, node any) error {
+	colls, err := compiler.Collections(node)
+	if err != nil {
+		return err
+	}
+
+	g := &generator{names: make(map[string]bool), references: make(map[string]string)}
+
+	// The collections are named first, so that the other declarations are
+	// renamed when their names collide.
+	names := make([]string, len(colls))
+	for i, coll := range colls {
+		names[i] = unique(g.names, Name(coll.Name))
+	}
+
+	for i, coll := range colls {
+		if err := g.collection(coll, names[i]); err != nil {
+			return fmt.Errorf("collection %s: %w", coll.Name, err)
+		}
+	}
+
+	src, err := format.Source(g.file(c.pkg()))
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(src)
+
+	return err
+}
+
+func (c *Config) pkg() string {
+	if c.Package == "" {
+		return DefaultPackage
+	}
+
+	return c.Package
+}
+
+type generator struct {
+	// decls are the declarations of the collection and nested structs.
+	decls []string
+	// names are the names of the declared types.
+	names map[string]bool
+
+	// references are the names of the reference types of the foreign
+	// collections, reference and publicKey are the names of the shared types,
+	// which are empty if the types are not used, and json reports whether the
+	// package is imported.
+	references map[string]string
+	reference  string
+	publicKey  string
+	json       bool
+}
+
+// unique returns the name, followed by underscores if it is already in names,
+// and adds it to names.
+func unique(names map[string]bool, name string) string {
+	for names[name] {
+		name += "_"
+	}
+
+	names[name] = true
+
+	return name
+}
+
+func (g *generator) file(pkg string) []byte {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "// Code generated by polylang-gen-go. DO NOT EDIT.\n\npackage %s\n", pkg)
+
+	if g.json {
+		buf.WriteString("\nimport \"encoding/json\"\n")
+	}
+
+	for _, decl := range g.decls {
+		buf.WriteString("\n" + decl)
+	}
+
+	names := make([]string, 0, len(g.references))
+	for name := range g.references {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(&buf, "\n// %[1]s is a reference to a record of the %[2]s collection.\n"+
+			"type %[1]s %[3]s\n", g.references[name], name, g.reference)
+	}
+
+	if g.reference != "" {
+		fmt.Fprintf(&buf, "\n// %[1]s is a reference to a record of a collection.\n"+
+			"type %[1]s struct {\n"+
+			"CollectionID string `json:\"collectionId\"`\n"+
+			"ID string `json:\"id\"`\n"+
+			"}\n", g.reference)
+	}
+
+	if g.publicKey != "" {
+		fmt.Fprintf(&buf, "\n// %[1]s is a public key in the JSON Web Key format.\n"+
+			"type %[1]s struct {\n"+
+			"Kty string `json:\"kty\"`\n"+
+			"Crv string `json:\"crv\"`\n"+
+			"Alg string `json:\"alg\"`\n"+
+			"Use string `json:\"use\"`\n"+
+			"X string `json:\"x\"`\n"+
+			"Y string `json:\"y\"`\n"+
+			"}\n", g.publicKey)
+	}
+
+	return buf.Bytes()
+}
+
+func (g *generator) collection(coll *metadata.Collection, name string) error {
+	var fields []metadata.ObjectField
+
+	for _, attr := range coll.Attributes {
+		prop, ok, err := attr.Property()
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			fields = append(fields, metadata.ObjectField{
+				Name: prop.Name, Type: prop.Type, Required: prop.Required,
+			})
+		}
+	}
+
+	return g.structType(name, fmt.Sprintf("%s is a record of the %s collection.", name, coll.Name), fields)
+}
+
+// structType declares a struct named name with the fields. The declaration is
+// placed before the declarations of its nested structs.
+func (g *generator) structType(name, doc string, fields []metadata.ObjectField) error {
+	i := len(g.decls)
+	g.decls = append(g.decls, "")
+
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "// %s\ntype %s struct {\n", doc, name)
+
+	// Fields whose names are the same in Go are renamed, such as foo_bar and
+	// fooBar.
+	names := make(map[string]bool)
+
+	for _, field := range fields {
+		fieldName := unique(names, Name(field.Name))
+
+		typ, nilable, err := g.typ(field.Type, name+fieldName,
+			fmt.Sprintf("the %s field of %s", field.Name, name))
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+
+		tag := field.Name
+
+		if !field.Required {
+			if !nilable {
+				typ = "*" + typ
+			}
+
+			tag += ",omitempty"
+		}
+
+		fmt.Fprintf(&buf, "%s %s `json:%s`\n", fieldName, typ, strconv.Quote(tag))
+	}
+
+	buf.WriteString("}\n")
+
+	g.decls[i] = buf.String()
+
+	return nil
+}
+
+// typ returns the Go type of a Polylang type, and whether its zero value is
+// nil. Nested structs are named name and documented as the type of what.
+func (g *generator) typ(t metadata.Type, name, what string) (string, bool, error) {
+	switch t.Kind {
+	case "primitive":
+		primitive, _, err := t.Primitive()
+		if err != nil {
+			return "", false, err
+		}
+
+		switch primitive.Value {
+		case metadata.PrimitiveTypeString:
+			return "string", false, nil
+		case metadata.PrimitiveTypeNumber:
+			return "float64", false, nil
+		case metadata.PrimitiveTypeBoolean:
+			return "bool", false, nil
+		case metadata.PrimitiveTypeBytes:
+			return "[]byte", true, nil
+		default:
+			return "", false, fmt.Errorf("unknown primitive type %q", primitive.Value)
+		}
+	case "publickey":
+		if g.publicKey == "" {
+			g.publicKey = unique(g.names, "PublicKey")
+		}
+
+		return g.publicKey, false, nil
+	case "record":
+		return g.referenceType(), false, nil
+	case "foreignrecord":
+		rec, _, err := t.ForeignRecord()
+		if err != nil {
+			return "", false, err
+		}
+
+		g.referenceType()
+
+		if _, ok := g.references[rec.Collection]; !ok {
+			g.references[rec.Collection] = unique(g.names, Name(rec.Collection)+"Reference")
+		}
+
+		return g.references[rec.Collection], false, nil
+	case "array":
+		array, _, err := t.Array()
+		if err != nil {
+			return "", false, err
+		}
+
+		elem, _, err := g.typ(array.Value, name+"Item", "an item of "+what)
+		if err != nil {
+			return "", false, err
+		}
+
+		return "[]" + elem, true, nil
+	case "map":
+		mp, _, err := t.Map()
+		if err != nil {
+			return "", false, err
+		}
+
+		key, err := g.key(mp.Key)
+		if err != nil {
+			return "", false, err
+		}
+
+		value, _, err := g.typ(mp.Value, name+"Value", "a value of "+what)
+		if err != nil {
+			return "", false, err
+		}
+
+		return "map[" + key + "]" + value, true, nil
+	case "object":
+		obj, _, err := t.Object()
+		if err != nil {
+			return "", false, err
+		}
+
+		name = unique(g.names, name)
+
+		if err := g.structType(name, name+" is the type of "+what+".", obj.Fields); err != nil {
+			return "", false, err
+		}
+
+		return name, false, nil
+	default:
+		return "", false, fmt.Errorf("unknown type kind %q", t.Kind)
+	}
+}
+
+// referenceType returns the name of the shared reference type.
+func (g *generator) referenceType() string {
+	if g.reference == "" {
+		g.reference = unique(g.names, "Reference")
+	}
+
+	return g.reference
+}
+
+// key returns the Go type of a map key. Object keys are always strings in
+// JSON, so number keys are kept as json.Number.
+func (g *generator) key(t metadata.Type) (string, error) {
+	primitive, ok, err := t.Primitive()
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case ok && primitive.Value == metadata.PrimitiveTypeString:
+		return "string", nil
+	case ok && primitive.Value == metadata.PrimitiveTypeNumber:
+		g.json = true
+
+		return "json.Number", nil
+	default:
+		return "", fmt.Errorf("invalid map key type %s", t.Kind)
+	}
+}
@@ -0,0 +1,153 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package gogen_test
+
+import (
+	"bytes"
+	"go/ast"
+	"go/importer"
+	goparser "go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/durudex/go-polylang/compiler"
+	"github.com/durudex/go-polylang/gogen"
+	"github.com/durudex/go-polylang/metadata"
+	"github.com/durudex/go-polylang/parser"
+)
+
+func TestFprint(t *testing.T) {
+	prog, err := parser.Parse("fixtures/users.polylang")
+	if err != nil {
+		t.Fatal("error: parsing polylang code: ", err)
+	}
+
+	root, err := compiler.Compile(prog, "ns")
+	if err != nil {
+		t.Fatal("error: compiling program: ", err)
+	}
+
+	want, err := os.ReadFile("fixtures/users.golden")
+	if err != nil {
+		t.Fatal("error: reading fixtures file: ", err)
+	}
+
+	for name, node := range map[string]any{"Program": prog, "Metadata": root} {
+		t.Run(name, func(t *testing.T) {
+			var got bytes.Buffer
+			if err := gogen.Fprint(&got, node); err != nil {
+				t.Fatal("error: generating code: ", err)
+			}
+
+			if got.String() != string(want) {
+				t.Fatalf("error: code does not match:\n%s", got.String())
+			}
+
+			typeCheck(t, got.Bytes())
+		})
+	}
+}
+
+func TestConfig_Fprint(t *testing.T) {
+	prog, err := parser.Must.ParseString("", "collection Account { id: string; }")
+	if err != nil {
+		t.Fatal("error: parsing polylang code: ", err)
+	}
+
+	var got bytes.Buffer
+	if err := (&gogen.Config{Package: "models"}).Fprint(&got, prog.Nodes[0].Collection); err != nil {
+		t.Fatal("error: generating code: ", err)
+	}
+
+	if !strings.Contains(got.String(), "\npackage models\n") {
+		t.Fatalf("error: package does not match:\n%s", got.String())
+	}
+}
+
+func TestFprint_Error(t *testing.T) {
+	data := `[{"kind":"collection","namespace":{"kind":"namespace","value":"ns"},"name":"T","attributes":[` +
+		`{"kind":"property","name":"a","type":{"kind":"map","key":{"kind":"publickey"},"value":{"kind":"primitive","value":"string"}},"directives":[],"required":true}]}]`
+
+	root, err := metadata.Parse([]byte(data))
+	if err != nil {
+		t.Fatal("error: parsing metadata: ", err)
+	}
+
+	if err := gogen.Fprint(&bytes.Buffer{}, root); err == nil {
+		t.Fatal("error: expected an error for an invalid map key")
+	}
+}
+
+// typeCheck reports an error if the generated code is not valid Go.
+func typeCheck(t *testing.T, src []byte) {
+	fset := token.NewFileSet()
+
+	file, err := goparser.ParseFile(fset, "collections.go", src, 0)
+	if err != nil {
+		t.Fatal("error: parsing go code: ", err)
+	}
+
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err := conf.Check("collections", fset, []*ast.File{file}, nil); err != nil {
+		t.Fatalf("error: type checking go code: %s\n%s", err, src)
+	}
+}
+
+func TestFprint_Collisions(t *testing.T) {
+	code := "collection Reference { id: string; owner: PublicKey; user: User; }\n" +
+		"collection PublicKey { id: string; }\n" +
+		"collection User { id: string; foo_bar: string; fooBar: map<number, string>; info: { a: record; }; }\n" +
+		"collection UserInfo { id: string; }\n" +
+		"collection UserReference { id: string; }\n"
+
+	prog, err := parser.Must.ParseString("", code)
+	if err != nil {
+		t.Fatal("error: parsing polylang code: ", err)
+	}
+
+	var got bytes.Buffer
+	if err := gogen.Fprint(&got, prog); err != nil {
+		t.Fatal("error: generating code: ", err)
+	}
+
+	typeCheck(t, got.Bytes())
+
+	for _, want := range []string{
+		"type Reference_ struct", "type PublicKey_ struct", "type UserReference_ Reference_",
+		"type UserInfo_ struct", "FooBar_ map[json.Number]string",
+	} {
+		if !strings.Contains(got.String(), want) {
+			t.Fatalf("error: code does not contain %q:\n%s", want, got.String())
+		}
+	}
+}
+
+var NameTests = map[string]struct {
+	name string
+	want string
+}{
+	"Lower":      {name: "name", want: "Name"},
+	"Camel":      {name: "publicKey", want: "PublicKey"},
+	"Snake":      {name: "website_url", want: "WebsiteURL"},
+	"Initialism": {name: "userId", want: "UserID"},
+	"Upper":      {name: "ID", want: "ID"},
+	"Digit":      {name: "_1st", want: "X1st"},
+}
+
+func TestName(t *testing.T) {
+	for name, test := range NameTests {
+		t.Run(name, func(t *testing.T) {
+			if got := gogen.Name(test.name); got != test.want {
+				t.Fatal("error: name does not match: ", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package gogen
+
+import (
+	"strings"
+	"unicode"
+)
+
+// initialisms are the words that are written in upper case in Go names.
+var initialisms = map[string]bool{
+	"API": true, "HTML": true, "HTTP": true, "ID": true, "JSON": true,
+	"URI": true, "URL": true, "UUID": true,
+}
+
+// Name returns the exported Go name of a Polylang name, such as UserID for
+// userId or user_id.
+func Name(name string) string {
+	var b strings.Builder
+
+	for _, word := range words(name) {
+		if upper := strings.ToUpper(word); initialisms[upper] {
+			b.WriteString(upper)
+		} else {
+			runes := []rune(word)
+			b.WriteString(string(unicode.ToUpper(runes[0])) + string(runes[1:]))
+		}
+	}
+
+	out := b.String()
+	if out == "" || !unicode.IsLetter([]rune(out)[0]) {
+		out = "X" + out
+	}
+
+	return out
+}
+
+// words splits a name at underscores and before upper case letters that
+// follow lower case letters or digits.
+func words(name string) []string {
+	var (
+		out   []string
+		start int
+	)
+
+	runes := []rune(name)
+
+	for i, r := range runes {
+		switch {
+		case r == '_':
+			if i > start {
+				out = append(out, string(runes[start:i]))
+			}
+
+			start = i + 1
+		case i > start && unicode.IsUpper(r) && !unicode.IsUpper(runes[i-1]):
+			out = append(out, string(runes[start:i]))
+			start = i
+		}
+	}
+
+	if start < len(runes) {
+		out = append(out, string(runes[start:]))
+	}
+
+	return out
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Package gencmd implements the command line of the code generators.
+package gencmd
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/durudex/go-polylang/metadata"
+	"github.com/durudex/go-polylang/parser"
+)
+
+// Generator writes the code generated for the collections of a node, which is
+// any node accepted by compiler.Collections.
+type Generator func(w io.Writer, node any) error
+
+// Main parses the command line of the command name, runs the generator on
+// the path argument and exits. The flags of the generator must be defined
+// before, and what describes the generated code in the usage.
+func Main(name, what string, generate Generator) {
+	var output string
+
+	flag.StringVar(&output, "o", "", "write the "+what+" to file instead of stdout")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s [flags] path\n", name)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	os.Exit(Run(generate, flag.Arg(0), output, os.Stdout, os.Stderr))
+}
+
+// Run generates the code of the collections at the path and returns the exit
+// code of the command. The code is written to the output file, or to out if
+// the output is empty.
+func Run(generate Generator, path, output string, out, errOut io.Writer) int {
+	node, err := Load(path)
+	if err != nil {
+		fmt.Fprintln(errOut, err)
+
+		return 1
+	}
+
+	var buf bytes.Buffer
+
+	if err := generate(&buf, node); err != nil {
+		fmt.Fprintf(errOut, "%s: %s\n", path, err)
+
+		return 1
+	}
+
+	if output != "" {
+		err = os.WriteFile(output, buf.Bytes(), 0o644)
+	} else {
+		_, err = out.Write(buf.Bytes())
+	}
+
+	if err != nil {
+		fmt.Fprintln(errOut, err)
+
+		return 1
+	}
+
+	return 0
+}
+
+// Load parses the collection metadata of a JSON file, or else the Polylang
+// code of a file or directory.
+func Load(path string) (any, error) {
+	if filepath.Ext(path) == ".json" {
+		return metadata.ParseFile(path)
+	}
+
+	return parser.Parse(path)
+}
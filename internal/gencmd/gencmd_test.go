@@ -0,0 +1,98 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package gencmd_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/durudex/go-polylang/compiler"
+	"github.com/durudex/go-polylang/internal/gencmd"
+)
+
+// names writes the names of the collections.
+func names(w io.Writer, node any) error {
+	colls, err := compiler.Collections(node)
+	if err != nil {
+		return err
+	}
+
+	for _, coll := range colls {
+		fmt.Fprintln(w, coll.Name)
+	}
+
+	return nil
+}
+
+func TestRun(t *testing.T) {
+	tests := map[string]string{
+		"Polylang": "../../compiler/fixtures/users.polylang",
+		"Metadata": "../../metadata/fixtures/collection.json",
+	}
+
+	for name, path := range tests {
+		t.Run(name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+
+			if code := gencmd.Run(names, path, "", &out, &errOut); code != 0 {
+				t.Fatal("error: unexpected exit code: ", errOut.String())
+			}
+
+			if out.String() != "Users\n" {
+				t.Fatal("error: output does not match: ", out.String())
+			}
+		})
+	}
+}
+
+func TestRun_Output(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.txt")
+
+	var out, errOut bytes.Buffer
+
+	if code := gencmd.Run(names, "../../compiler/fixtures/users.polylang", path, &out, &errOut); code != 0 {
+		t.Fatal("error: unexpected exit code: ", errOut.String())
+	}
+
+	if out.Len() != 0 {
+		t.Fatal("error: unexpected output: ", out.String())
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "Users\n" {
+		t.Fatal("error: output file does not match: ", err)
+	}
+}
+
+func TestRun_Error(t *testing.T) {
+	tests := map[string]struct {
+		path     string
+		generate gencmd.Generator
+	}{
+		"Missing": {path: "missing.polylang", generate: names},
+		"Generator": {
+			path:     "../../compiler/fixtures/users.polylang",
+			generate: func(io.Writer, any) error { return errors.New("failed") },
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+
+			if code := gencmd.Run(test.generate, test.path, "", &out, &errOut); code != 1 || errOut.Len() == 0 {
+				t.Fatal("error: expected an error")
+			}
+		})
+	}
+}
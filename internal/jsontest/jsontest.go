@@ -0,0 +1,50 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Package jsontest implements helpers for tests that compare JSON documents.
+package jsontest
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/durudex/go-polylang/compiler"
+)
+
+// Normalize decodes JSON into plain values, so that documents can be compared
+// regardless of their formatting.
+func Normalize(t *testing.T, data []byte) any {
+	t.Helper()
+
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatal("error: unmarshal json: ", err)
+	}
+
+	return v
+}
+
+// RenameReturnValues gives every return value in normalized metadata the name
+// that the compiler uses, as Polylang code does not name them.
+func RenameReturnValues(v any) any {
+	switch v := v.(type) {
+	case []any:
+		for _, elem := range v {
+			RenameReturnValues(elem)
+		}
+	case map[string]any:
+		if v["kind"] == "returnvalue" {
+			v["name"] = compiler.ReturnValueName
+		}
+
+		for _, elem := range v {
+			RenameReturnValues(elem)
+		}
+	}
+
+	return v
+}
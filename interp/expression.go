@@ -0,0 +1,542 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package interp
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+
+	"github.com/durudex/go-polylang/ast"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+// builtin is a function available to all code unless a variable of the same
+// name hides it.
+type builtin func(pos lexer.Position, args []any) (any, error)
+
+var builtins = map[string]builtin{
+	// error creates a value to be thrown with the message.
+	"error": func(pos lexer.Position, args []any) (any, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("%s: error takes 1 argument, got %d", pos, len(args))
+		}
+
+		return &Error{Pos: pos, Message: format(args[0])}, nil
+	},
+}
+
+func (in *interpreter) expression(expr *ast.Expression) (any, error) {
+	switch {
+	case expr.Unary != nil:
+		return in.unary(expr.Unary)
+	case expr.Update != nil:
+		return in.update(expr.Update)
+	case expr.Binary != nil:
+		return in.binary(expr.Binary)
+	case expr.Call != nil:
+		return in.call(expr.Call)
+	case expr.Member != nil:
+		object, err := in.expression(expr.Member.Object)
+		if err != nil {
+			return nil, err
+		}
+
+		return member(expr.Member.Pos, object, expr.Member.Property)
+	case expr.Index != nil:
+		object, index, err := in.index(expr.Index)
+		if err != nil {
+			return nil, err
+		}
+
+		return element(expr.Index.Pos, object, index)
+	case expr.Array != nil:
+		array := make([]any, len(expr.Array.Elements))
+
+		for i, element := range expr.Array.Elements {
+			v, err := in.expression(element)
+			if err != nil {
+				return nil, err
+			}
+
+			array[i] = v
+		}
+
+		return array, nil
+	case expr.Object != nil:
+		object := make(map[string]any, len(expr.Object.Properties))
+
+		for _, property := range expr.Object.Properties {
+			v, err := in.expression(property.Value)
+			if err != nil {
+				return nil, err
+			}
+
+			object[property.Key] = v
+		}
+
+		return object, nil
+	case expr.Value != nil:
+		return in.value(expr.Value)
+	default:
+		return nil, nil
+	}
+}
+
+func (in *interpreter) value(value *ast.Value) (any, error) {
+	switch {
+	case value.Number != nil:
+		// Numbers out of range are infinite, as in JavaScript, and the parser
+		// does not accept malformed ones.
+		f, _ := value.Number.Float64()
+
+		return f, nil
+	case value.String != nil:
+		return value.String.Value, nil
+	case value.Ident != nil:
+		s, ok := in.scope.lookup(*value.Ident)
+		if !ok {
+			return nil, fmt.Errorf("%s: %s is not defined", value.Pos, *value.Ident)
+		}
+
+		return s.vars[*value.Ident], nil
+	case value.Sub != nil:
+		return in.expression(value.Sub)
+	case value.Boolean != nil:
+		return *value.Boolean, nil
+	default:
+		return nil, fmt.Errorf("%s: empty value", value.Pos)
+	}
+}
+
+func (in *interpreter) call(expr *ast.CallExpr) (any, error) {
+	name := expr.Callee.Value
+	if name == nil || name.Ident == nil {
+		return nil, fmt.Errorf("%s: expression is not a function", expr.Callee.Pos)
+	}
+
+	fn, ok := builtins[*name.Ident]
+	if _, declared := in.scope.lookup(*name.Ident); declared || !ok {
+		return nil, fmt.Errorf("%s: %s is not a function", expr.Callee.Pos, *name.Ident)
+	}
+
+	args := make([]any, len(expr.Arguments))
+
+	for i, arg := range expr.Arguments {
+		value, err := in.expression(arg)
+		if err != nil {
+			return nil, err
+		}
+
+		args[i] = value
+	}
+
+	return fn(expr.Pos, args)
+}
+
+func member(pos lexer.Position, object any, property string) (any, error) {
+	switch object := object.(type) {
+	case map[string]any:
+		return object[property], nil
+	case string:
+		if property == "length" {
+			return float64(len(object)), nil
+		}
+	case []any:
+		if property == "length" {
+			return float64(len(object)), nil
+		}
+	case nil:
+		return nil, fmt.Errorf("%s: cannot read %s of undefined", pos, property)
+	}
+
+	return nil, fmt.Errorf("%s: %s has no property %s", pos, typeName(object), property)
+}
+
+func (in *interpreter) index(expr *ast.IndexExpr) (any, any, error) {
+	object, err := in.expression(expr.Object)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	index, err := in.expression(expr.Index)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return object, index, nil
+}
+
+func element(pos lexer.Position, object, index any) (any, error) {
+	switch object := object.(type) {
+	case map[string]any:
+		key, ok := index.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: key must be string, not %s", pos, typeName(index))
+		}
+
+		return object[key], nil
+	case []any:
+		i, err := arrayIndex(pos, object, index)
+		if err != nil {
+			return nil, err
+		}
+
+		return object[i], nil
+	default:
+		return nil, fmt.Errorf("%s: cannot index %s", pos, typeName(object))
+	}
+}
+
+func arrayIndex(pos lexer.Position, array []any, index any) (int, error) {
+	n, ok := index.(float64)
+	if !ok || n != math.Trunc(n) {
+		return 0, fmt.Errorf("%s: array index must be an integer, not %s", pos, typeName(index))
+	}
+
+	if n < 0 || n >= float64(len(array)) {
+		return 0, fmt.Errorf("%s: array index %s out of range", pos, format(n))
+	}
+
+	return int(n), nil
+}
+
+func (in *interpreter) unary(expr *ast.UnaryExpr) (any, error) {
+	operand, err := in.expression(expr.Operand)
+	if err != nil {
+		return nil, err
+	}
+
+	if expr.Operator == ast.Not {
+		return !truthy(operand), nil
+	}
+
+	n, ok := operand.(float64)
+	if !ok {
+		return nil, fmt.Errorf("%s: operator %s needs number, not %s", expr.Pos, expr.Operator, typeName(operand))
+	}
+
+	switch expr.Operator {
+	case ast.BitNot:
+		return float64(^toInt32(n)), nil
+	case ast.Add:
+		return n, nil
+	default:
+		return -n, nil
+	}
+}
+
+// update increments or decrements its operand, and returns the number before
+// the change if the operator is written after the operand.
+func (in *interpreter) update(expr *ast.UpdateExpr) (any, error) {
+	ref, err := in.reference(expr.Operand)
+	if err != nil {
+		return nil, err
+	}
+
+	operand, err := ref.load()
+	if err != nil {
+		return nil, err
+	}
+
+	n, ok := operand.(float64)
+	if !ok {
+		return nil, fmt.Errorf("%s: operator %s needs number, not %s", expr.Pos, expr.Operator, typeName(operand))
+	}
+
+	updated := n + 1
+	if expr.Operator == ast.Decrement {
+		updated = n - 1
+	}
+
+	if err := ref.store(updated); err != nil {
+		return nil, err
+	}
+
+	if expr.Prefix {
+		return updated, nil
+	}
+
+	return n, nil
+}
+
+func (in *interpreter) binary(expr *ast.BinaryExpr) (any, error) {
+	switch expr.Operator {
+	case ast.Assign, ast.AssignAdd, ast.AssignSub:
+		return in.assign(expr)
+	case ast.And, ast.Or:
+		left, err := in.expression(expr.Left)
+		if err != nil || truthy(left) == (expr.Operator == ast.Or) {
+			return left, err
+		}
+
+		return in.expression(expr.Right)
+	}
+
+	left, err := in.expression(expr.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := in.expression(expr.Right)
+	if err != nil {
+		return nil, err
+	}
+
+	return operate(expr.Pos, expr.Operator, left, right)
+}
+
+// assign stores the value in the variable, field or element on the left.
+func (in *interpreter) assign(expr *ast.BinaryExpr) (any, error) {
+	ref, err := in.reference(expr.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := in.expression(expr.Right)
+	if err != nil {
+		return nil, err
+	}
+
+	if expr.Operator != ast.Assign {
+		current, err := ref.load()
+		if err != nil {
+			return nil, err
+		}
+
+		op := ast.Add
+		if expr.Operator == ast.AssignSub {
+			op = ast.Subtract
+		}
+
+		if value, err = operate(expr.Pos, op, current, value); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := ref.store(value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// reference is a variable, member or element that an expression refers to.
+// Its object and key are evaluated once, so that they are not evaluated again
+// when the value is loaded and then stored.
+type reference struct {
+	pos    lexer.Position
+	object any
+	key    any
+
+	// property reports whether the key is the name of a member rather than
+	// an element index.
+	property bool
+}
+
+// reference resolves the variable, member or element that the target
+// expression refers to.
+func (in *interpreter) reference(target *ast.Expression) (*reference, error) {
+	switch {
+	case target.Value != nil && target.Value.Ident != nil:
+		name := *target.Value.Ident
+
+		s, ok := in.scope.lookup(name)
+		if !ok {
+			return nil, fmt.Errorf("%s: %s is not defined", target.Pos, name)
+		}
+
+		return &reference{pos: target.Pos, object: s.vars, key: name}, nil
+	case target.Member != nil:
+		object, err := in.expression(target.Member.Object)
+		if err != nil {
+			return nil, err
+		}
+
+		return &reference{pos: target.Pos, object: object, key: target.Member.Property, property: true}, nil
+	case target.Index != nil:
+		object, index, err := in.index(target.Index)
+		if err != nil {
+			return nil, err
+		}
+
+		return &reference{pos: target.Pos, object: object, key: index}, nil
+	default:
+		return nil, fmt.Errorf("%s: cannot assign to expression", target.Pos)
+	}
+}
+
+func (r *reference) load() (any, error) {
+	if r.property {
+		return member(r.pos, r.object, r.key.(string))
+	}
+
+	return element(r.pos, r.object, r.key)
+}
+
+func (r *reference) store(value any) error {
+	if !r.property {
+		return setElement(r.pos, r.object, r.key, value)
+	}
+
+	m, ok := r.object.(map[string]any)
+	if !ok {
+		return fmt.Errorf("%s: cannot set %s of %s", r.pos, r.key, typeName(r.object))
+	}
+
+	m[r.key.(string)] = value
+
+	return nil
+}
+
+func setElement(pos lexer.Position, object, index, value any) error {
+	switch object := object.(type) {
+	case map[string]any:
+		key, ok := index.(string)
+		if !ok {
+			return fmt.Errorf("%s: key must be string, not %s", pos, typeName(index))
+		}
+
+		object[key] = value
+	case []any:
+		i, err := arrayIndex(pos, object, index)
+		if err != nil {
+			return err
+		}
+
+		object[i] = value
+	default:
+		return fmt.Errorf("%s: cannot index %s", pos, typeName(object))
+	}
+
+	return nil
+}
+
+func operate(pos lexer.Position, op ast.Operator, left, right any) (any, error) {
+	switch op {
+	case ast.Equal:
+		return reflect.DeepEqual(left, right), nil
+	case ast.NotEqual:
+		return !reflect.DeepEqual(left, right), nil
+	case ast.Add:
+		if _, ok := left.(string); ok {
+			return format(left) + format(right), nil
+		}
+
+		if _, ok := right.(string); ok {
+			return format(left) + format(right), nil
+		}
+	case ast.LessThan, ast.GreaterThan, ast.LessThanOrEqual, ast.GreaterThanOrEqual:
+		if l, ok := left.(string); ok {
+			if r, ok := right.(string); ok {
+				return compare(op, l, r), nil
+			}
+		}
+	}
+
+	l, lok := left.(float64)
+	r, rok := right.(float64)
+
+	if !lok || !rok {
+		return nil, fmt.Errorf("%s: operator %s needs numbers, not %s and %s",
+			pos, op, typeName(left), typeName(right))
+	}
+
+	switch op {
+	case ast.Add:
+		return l + r, nil
+	case ast.Subtract:
+		return l - r, nil
+	case ast.Multiply:
+		return l * r, nil
+	case ast.Divide:
+		return l / r, nil
+	case ast.Modulo:
+		return math.Mod(l, r), nil
+	case ast.Exponent:
+		return math.Pow(l, r), nil
+	case ast.ShiftLeft:
+		return float64(toInt32(l) << shiftCount(r)), nil
+	case ast.ShiftRight:
+		return float64(toInt32(l) >> shiftCount(r)), nil
+	case ast.BitAnd:
+		return float64(toInt32(l) & toInt32(r)), nil
+	case ast.BitXor:
+		return float64(toInt32(l) ^ toInt32(r)), nil
+	case ast.BitOr:
+		return float64(toInt32(l) | toInt32(r)), nil
+	case ast.LessThan, ast.GreaterThan, ast.LessThanOrEqual, ast.GreaterThanOrEqual:
+		return compare(op, l, r), nil
+	default:
+		return nil, fmt.Errorf("%s: unsupported operator %s", pos, op)
+	}
+}
+
+func compare[T float64 | string](op ast.Operator, l, r T) bool {
+	switch op {
+	case ast.LessThan:
+		return l < r
+	case ast.GreaterThan:
+		return l > r
+	case ast.LessThanOrEqual:
+		return l <= r
+	default:
+		return l >= r
+	}
+}
+
+// toInt32 converts a number to the 32-bit integer used by bitwise operators,
+// wrapping it modulo 2^32 as in JavaScript. NaN and infinities become 0.
+func toInt32(n float64) int32 {
+	if math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0
+	}
+
+	return int32(uint32(int64(math.Mod(math.Trunc(n), 1<<32))))
+}
+
+// shiftCount returns the number of bits to shift by, which is taken modulo 32
+// as in JavaScript, so that negative and large counts are not undefined.
+func shiftCount(n float64) uint32 {
+	return uint32(toInt32(n) & 31)
+}
+
+// format returns a value as it is joined to a string.
+func format(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case nil:
+		return "undefined"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func typeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "undefined"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
@@ -0,0 +1,157 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Package interp executes Polylang functions without a Polybase node, which
+// allows the logic of collections to be tested locally.
+//
+// Values are represented with plain Go values: numbers are float64, strings
+// are string, booleans are bool, arrays are []any and records, objects and
+// maps are map[string]any. Missing values are nil.
+package interp
+
+import (
+	"fmt"
+
+	"github.com/durudex/go-polylang/ast"
+	"github.com/durudex/go-polylang/decompiler"
+	"github.com/durudex/go-polylang/metadata"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+// Context is the authentication context of a call, available to functions as
+// ctx.
+type Context struct {
+	// PublicKey of the caller, or nil for an anonymous call.
+	PublicKey any
+}
+
+// Result of a call.
+type Result struct {
+	// This is the record after the call.
+	This map[string]any
+	// Value returned by the function, or nil.
+	Value any
+}
+
+// Error is a value thrown by a function.
+type Error struct {
+	Pos     lexer.Position
+	Message string
+}
+
+func (e *Error) Error() string { return e.Message }
+
+// Call executes the function with the record as this. The record is copied, so
+// the mutated record is only returned in the result. A thrown value is
+// returned as an *Error.
+func Call(fn *ast.Function, this map[string]any, args []any, ctx Context) (*Result, error) {
+	if len(args) > len(fn.Parameters) {
+		return nil, fmt.Errorf("function %s: got %d arguments, want %d", fn.Name, len(args), len(fn.Parameters))
+	}
+
+	out := &Result{}
+	if out.This, _ = clone(this).(map[string]any); out.This == nil {
+		out.This = map[string]any{}
+	}
+
+	in := &interpreter{}
+	in.push()
+
+	in.scope.vars["this"] = out.This
+	in.scope.vars["ctx"] = map[string]any{"publicKey": ctx.PublicKey}
+
+	for i, param := range fn.Parameters {
+		var arg any
+		if i < len(args) {
+			arg = clone(args[i])
+		}
+
+		if arg == nil && !param.Optional {
+			return nil, fmt.Errorf("function %s: missing argument %s", fn.Name, param.Name)
+		}
+
+		in.scope.vars[param.Name] = arg
+	}
+
+	_, value, err := in.statements(fn.Statements)
+	if err != nil {
+		return nil, err
+	}
+
+	out.Value = value
+
+	return out, nil
+}
+
+// CallMethod executes a method of collection metadata, whose code is parsed
+// first.
+func CallMethod(method *metadata.Method, this map[string]any, args []any, ctx Context) (*Result, error) {
+	item, err := decompiler.Method(method)
+	if err != nil {
+		return nil, err
+	}
+
+	return Call(item.Function, this, args, ctx)
+}
+
+// scope holds the variables declared in a block.
+type scope struct {
+	parent *scope
+	vars   map[string]any
+}
+
+func (s *scope) lookup(name string) (*scope, bool) {
+	for ; s != nil; s = s.parent {
+		if _, ok := s.vars[name]; ok {
+			return s, true
+		}
+	}
+
+	return nil, false
+}
+
+type interpreter struct {
+	scope *scope
+}
+
+func (in *interpreter) push() {
+	in.scope = &scope{parent: in.scope, vars: map[string]any{}}
+}
+
+func (in *interpreter) pop() { in.scope = in.scope.parent }
+
+// clone returns a deep copy of a value, so that calls never change the values
+// passed to them.
+func clone(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		if v == nil {
+			return v
+		}
+
+		out := make(map[string]any, len(v))
+		for key, value := range v {
+			out[key] = clone(value)
+		}
+
+		return out
+	case []any:
+		if v == nil {
+			return v
+		}
+
+		out := make([]any, len(v))
+		for i, value := range v {
+			out[i] = clone(value)
+		}
+
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,203 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package interp_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/durudex/go-polylang/interp"
+	"github.com/durudex/go-polylang/metadata"
+	"github.com/durudex/go-polylang/parser"
+)
+
+var CallTests = map[string]struct {
+	code string
+	this map[string]any
+	args []any
+	ctx  interp.Context
+	want *interp.Result
+}{
+	"Constructor": {
+		code: "function constructor(id: string, age?: number) { this.id = id; this.owner = ctx.publicKey; if (age) this.age = age; }",
+		args: []any{"a"},
+		ctx:  interp.Context{PublicKey: "key"},
+		want: &interp.Result{This: map[string]any{"id": "a", "owner": "key"}},
+	},
+	"Return": {
+		code: "function f(a: number, b: number): number { return a * b + 1; }",
+		args: []any{2.0, 3.0},
+		want: &interp.Result{This: map[string]any{}, Value: 7.0},
+	},
+	"While": {
+		code: "function f(n: number): number { let i = 0; while (true) { if (i >= n) { break; } i += 1; } return i; }",
+		args: []any{5.0},
+		want: &interp.Result{This: map[string]any{}, Value: 5.0},
+	},
+	"For": {
+		code: "function f() { for (let i = 0; i < 3; i += 1) { this.items[i] = this.items[i] + '!'; } this.count = this.items.length; }",
+		this: map[string]any{"items": []any{"a", "b", "c"}},
+		want: &interp.Result{This: map[string]any{"items": []any{"a!", "b!", "c!"}, "count": 3.0}},
+	},
+	"Scope": {
+		code: "function f(): string { let a = 'outer'; if (true) { let a = 'inner'; } return a; }",
+		want: &interp.Result{This: map[string]any{}, Value: "outer"},
+	},
+	"Map": {
+		code: "function f(key: string) { this.balances[key] = (this.balances[key] || 0) + 10; this.info.name -= 0 - 1; }",
+		this: map[string]any{"balances": map[string]any{}, "info": map[string]any{"name": 1.0}},
+		args: []any{"a"},
+		want: &interp.Result{This: map[string]any{"balances": map[string]any{"a": 10.0}, "info": map[string]any{"name": 2.0}}},
+	},
+	"Literals": {
+		code: `function f(): string { return 'it\'s' + "\t\u{263A}" + 0x10 + 1.5e1; }`,
+		want: &interp.Result{This: map[string]any{}, Value: "it's\t\u263a1615"},
+	},
+	"Array and Object": {
+		code: "function f(name: string) { this.tags = []; this.info = { name: name, 'tags': [name, 1 + 1] }; this.first = [name][0]; }",
+		args: []any{"a"},
+		want: &interp.Result{This: map[string]any{
+			"tags": []any{}, "info": map[string]any{"name": "a", "tags": []any{"a", 2.0}}, "first": "a",
+		}},
+	},
+	"Update": {
+		code: "function f(n: number): number { let s = 0; for (let i = 0; i < n; i++) { s += i; } this.count = 1; let a = this.count++; let b = --this.count; return s * 100 + a * 10 + b + +2; }",
+		args: []any{4.0},
+		want: &interp.Result{This: map[string]any{"count": 1.0}, Value: 613.0},
+	},
+	"Statements": {
+		code: "function f(n: number) { this.odd = 0; for (let i = 0; i < n; i++) { if (i % 2 == 0) continue; else if (i > 5) { return; } " +
+			"{ let i = 0; } this.odd += 1; } this.done = true; }",
+		args: []any{10.0},
+		want: &interp.Result{This: map[string]any{"odd": 3.0}},
+	},
+	"Once": {
+		code: "function f(): number { let a = [1, 2]; let i = 0; a[i++]++; a[i++] += 10; return i * 100 + a[0] * 10 + a[1]; }",
+		want: &interp.Result{This: map[string]any{}, Value: 232.0},
+	},
+	"Shift": {
+		code: "function f(): boolean { return 1 << 32 == 1 && 1 << -1 == -2147483648 && -8 >> 33 == -4 && 1 << 31 < 0; }",
+		want: &interp.Result{This: map[string]any{}, Value: true},
+	},
+	"Bitwise": {
+		code: "function f(): boolean { return ~0x80000000 == 2147483647 && (0xFFFFFFFF & -1) == -1 && (0xFFFFFFFF | 0) == -1 && " +
+			"(0x100000001 ^ 0) == 1 && ~4294967296 == -1 && 1 << 0x100000001 == 2; }",
+		want: &interp.Result{This: map[string]any{}, Value: true},
+	},
+	"Operators": {
+		code: "function f(): boolean { return !(1 == 2) && 2 ** 3 == 8 && 7 % 4 == 3 && (6 & 3) == 2 && 1 << 2 == 4 && -1 < 0 && 'a' < 'b' && 'n' + 1 == 'n1'; }",
+		want: &interp.Result{This: map[string]any{}, Value: true},
+	},
+}
+
+func TestCall(t *testing.T) {
+	for name, test := range CallTests {
+		t.Run(name, func(t *testing.T) {
+			prog, err := parser.Must.ParseString("", test.code)
+			if err != nil {
+				t.Fatal("error: parsing polylang code: ", err)
+			}
+
+			got, err := interp.Call(prog.Nodes[0].Function, test.this, test.args, test.ctx)
+			if err != nil {
+				t.Fatal("error: calling function: ", err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("error: result does not match: %#v", got)
+			}
+		})
+	}
+}
+
+func TestCall_Copy(t *testing.T) {
+	prog, err := parser.Must.ParseString("", "function f(a: number[]) { this.n = 1; a[0] = 2; }")
+	if err != nil {
+		t.Fatal("error: parsing polylang code: ", err)
+	}
+
+	this, args := map[string]any{}, []any{[]any{1.0}}
+
+	if _, err := interp.Call(prog.Nodes[0].Function, this, args, interp.Context{}); err != nil {
+		t.Fatal("error: calling function: ", err)
+	}
+
+	if len(this) != 0 || args[0].([]any)[0] != 1.0 {
+		t.Fatal("error: call changed its arguments")
+	}
+}
+
+func TestCall_Throw(t *testing.T) {
+	prog, err := parser.Must.ParseString("", "function f() { if (this.owner != ctx.publicKey) { throw error('not owner'); } }")
+	if err != nil {
+		t.Fatal("error: parsing polylang code: ", err)
+	}
+
+	_, err = interp.Call(prog.Nodes[0].Function, map[string]any{"owner": "a"}, nil, interp.Context{PublicKey: "b"})
+
+	var thrown *interp.Error
+	if !errors.As(err, &thrown) || thrown.Message != "not owner" || thrown.Pos.Line != 1 {
+		t.Fatalf("error: expected thrown error: %v", err)
+	}
+}
+
+var CallErrorTests = map[string]struct {
+	code string
+	args []any
+}{
+	"Arguments":  {code: "function f() {}", args: []any{1.0}},
+	"Missing":    {code: "function f(a: string) {}"},
+	"Undefined":  {code: "function f() { a = 1; }"},
+	"Function":   {code: "function f() { g(); }"},
+	"Operand":    {code: "function f() { let a = 'a' - 1; }"},
+	"Property":   {code: "function f() { let a = this.a.b; }"},
+	"OutOfRange": {code: "function f(a: number[]) { a[1] = 1; }", args: []any{[]any{}}},
+	"LargeIndex": {code: "function f(a: number[]) { let b = a[1e300]; }", args: []any{[]any{1.0}}},
+}
+
+func TestCall_Error(t *testing.T) {
+	for name, test := range CallErrorTests {
+		t.Run(name, func(t *testing.T) {
+			prog, err := parser.Must.ParseString("", test.code)
+			if err != nil {
+				t.Fatal("error: parsing polylang code: ", err)
+			}
+
+			if _, err := interp.Call(prog.Nodes[0].Function, nil, test.args, interp.Context{}); err == nil {
+				t.Fatal("error: expected calling error")
+			}
+		})
+	}
+}
+
+func TestCallMethod(t *testing.T) {
+	root, err := metadata.ParseFile("../metadata/fixtures/collection.json")
+	if err != nil {
+		t.Fatal("error: parsing metadata: ", err)
+	}
+
+	coll, _, err := root[0].Collection()
+	if err != nil {
+		t.Fatal("error: node is not collection: ", err)
+	}
+
+	method, _, err := coll.Attributes[6].Method()
+	if err != nil {
+		t.Fatal("error: attribute is not method: ", err)
+	}
+
+	got, err := interp.CallMethod(method, map[string]any{"age": 1.0}, []any{2.0}, interp.Context{})
+	if err != nil {
+		t.Fatal("error: calling method: ", err)
+	}
+
+	if !reflect.DeepEqual(got.This, map[string]any{"age": 2.0}) {
+		t.Fatalf("error: record does not match: %v", got.This)
+	}
+}
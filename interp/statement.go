@@ -0,0 +1,213 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package interp
+
+import (
+	"fmt"
+
+	"github.com/durudex/go-polylang/ast"
+)
+
+// control tells how a statement ended.
+type control int
+
+const (
+	next control = iota
+	breaking
+	continuing
+	returning
+)
+
+func (in *interpreter) statements(statements []*ast.Statement) (control, any, error) {
+	in.push()
+	defer in.pop()
+
+	for _, stmt := range statements {
+		ctrl, value, err := in.statement(stmt)
+		if err != nil || ctrl != next {
+			return ctrl, value, err
+		}
+	}
+
+	return next, nil, nil
+}
+
+func (in *interpreter) statement(stmt *ast.Statement) (control, any, error) {
+	switch {
+	case stmt.Compound != nil:
+		return in.compound(stmt.Compound)
+	case stmt.Simple != nil:
+		return in.small(stmt.Simple.Small)
+	default:
+		return next, nil, nil
+	}
+}
+
+func (in *interpreter) small(stmt *ast.SmallStatement) (control, any, error) {
+	switch {
+	case stmt.Break:
+		return breaking, nil, nil
+	case stmt.Continue:
+		return continuing, nil, nil
+	case stmt.Return != nil && stmt.Return.Value == nil:
+		return returning, nil, nil
+	case stmt.Return != nil:
+		value, err := in.expression(stmt.Return.Value)
+
+		return returning, value, err
+	case stmt.Throw != nil:
+		return next, nil, in.throw(stmt.Throw)
+	case stmt.Let != nil:
+		return next, nil, in.let(stmt.Let)
+	case stmt.Expression != nil:
+		_, err := in.expression(stmt.Expression)
+
+		return next, nil, err
+	default:
+		return next, nil, nil
+	}
+}
+
+func (in *interpreter) throw(expr *ast.Expression) error {
+	value, err := in.expression(expr)
+	if err != nil {
+		return err
+	}
+
+	if thrown, ok := value.(*Error); ok {
+		return thrown
+	}
+
+	return &Error{Pos: expr.Pos, Message: fmt.Sprint(value)}
+}
+
+func (in *interpreter) let(let *ast.Let) error {
+	value, err := in.expression(let.Expression)
+	if err != nil {
+		return err
+	}
+
+	in.scope.vars[let.Ident] = value
+
+	return nil
+}
+
+func (in *interpreter) compound(stmt *ast.CompoundStatement) (control, any, error) {
+	switch {
+	case stmt.If != nil:
+		return in.ifStatement(stmt.If)
+	case stmt.While != nil:
+		return in.loop(stmt.While.Condition, nil, stmt.While.Statements)
+	case stmt.For != nil:
+		in.push()
+		defer in.pop()
+
+		if err := in.forInitial(stmt.For.Initial); err != nil {
+			return next, nil, err
+		}
+
+		return in.loop(stmt.For.Condition, stmt.For.Post, stmt.For.Statements)
+	case stmt.Block != nil:
+		return in.statements(stmt.Block.Statements)
+	default:
+		return next, nil, nil
+	}
+}
+
+func (in *interpreter) ifStatement(stmt *ast.If) (control, any, error) {
+	ok, err := in.condition(stmt.Condition)
+	if err != nil {
+		return next, nil, err
+	}
+
+	if ok {
+		return in.statementsOrSimple(stmt.Statement)
+	}
+
+	return in.statementsOrSimple(stmt.Else)
+}
+
+func (in *interpreter) statementsOrSimple(stmt *ast.StatementsOrSimple) (control, any, error) {
+	switch {
+	case stmt == nil:
+		return next, nil, nil
+	case stmt.Compound != nil:
+		return in.compound(stmt.Compound)
+	case stmt.Simple != nil:
+		in.push()
+		defer in.pop()
+
+		return in.small(stmt.Simple.Small)
+	default:
+		return in.statements(stmt.Statements)
+	}
+}
+
+func (in *interpreter) forInitial(initial *ast.ForInitial) error {
+	switch {
+	case initial.Let != nil:
+		return in.let(initial.Let)
+	case initial.Expression != nil:
+		_, err := in.expression(initial.Expression)
+
+		return err
+	default:
+		return nil
+	}
+}
+
+// loop runs the statements while the condition holds, evaluating post after
+// every iteration, including the ones that are continued.
+func (in *interpreter) loop(condition, post *ast.Expression, statements []*ast.Statement) (control, any, error) {
+	for {
+		ok, err := in.condition(condition)
+		if err != nil || !ok {
+			return next, nil, err
+		}
+
+		ctrl, value, err := in.statements(statements)
+		switch {
+		case err != nil || ctrl == returning:
+			return ctrl, value, err
+		case ctrl == breaking:
+			return next, nil, nil
+		}
+
+		if post != nil {
+			if _, err := in.expression(post); err != nil {
+				return next, nil, err
+			}
+		}
+	}
+}
+
+func (in *interpreter) condition(expr *ast.Expression) (bool, error) {
+	value, err := in.expression(expr)
+	if err != nil {
+		return false, err
+	}
+
+	return truthy(value), nil
+}
+
+// truthy reports whether a value holds in a condition. Missing values, false,
+// zero and empty strings do not.
+func truthy(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	default:
+		return true
+	}
+}
@@ -0,0 +1,260 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Package jsonschema generates JSON Schema documents for the records of
+// Polylang collections.
+package jsonschema
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/durudex/go-polylang/compiler"
+	"github.com/durudex/go-polylang/metadata"
+)
+
+// Draft is the dialect of the generated schemas.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Names of the shared definitions.
+const (
+	PublicKeyDef = "PublicKey"
+	ReferenceDef = "Reference"
+)
+
+// numberPattern matches the JSON numbers that are used as map keys.
+const numberPattern = `^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`
+
+// Schema is a JSON Schema with the keywords used for records.
+type Schema struct {
+	Schema string `json:"$schema,omitempty"`
+	Ref    string `json:"$ref,omitempty"`
+	Title  string `json:"title,omitempty"`
+
+	Type            string   `json:"type,omitempty"`
+	Enum            []string `json:"enum,omitempty"`
+	Pattern         string   `json:"pattern,omitempty"`
+	ContentEncoding string   `json:"contentEncoding,omitempty"`
+
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	Required   []string           `json:"required,omitempty"`
+	// AdditionalProperties is false for objects with fixed fields, and the
+	// schema of the values for maps.
+	AdditionalProperties any     `json:"additionalProperties,omitempty"`
+	PropertyNames        *Schema `json:"propertyNames,omitempty"`
+
+	Items *Schema `json:"items,omitempty"`
+
+	Defs map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// Generate returns a schema for the records of every collection of the node,
+// which is any node accepted by compiler.Collections.
+func Generate(node any) ([]*Schema, error) {
+	colls, err := compiler.Collections(node)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*Schema, 0, len(colls))
+
+	for _, coll := range colls {
+		schema, err := Collection(coll)
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, schema)
+	}
+
+	return out, nil
+}
+
+// Collection returns the schema of the records of a collection. Foreign
+// records, records and public keys refer to definitions in $defs, where the
+// reference to a record of a collection must have a collectionId that is its
+// name or ends with a slash and its name, as checked by the validate package.
+func Collection(coll *metadata.Collection) (*Schema, error) {
+	var fields []metadata.ObjectField
+
+	for _, attr := range coll.Attributes {
+		prop, ok, err := attr.Property()
+		if err != nil {
+			return nil, fmt.Errorf("collection %s: %w", coll.Name, err)
+		}
+
+		if ok {
+			fields = append(fields, metadata.ObjectField{
+				Name: prop.Name, Type: prop.Type, Required: prop.Required,
+			})
+		}
+	}
+
+	g := &generator{defs: make(map[string]*Schema)}
+
+	out, err := g.object(fields)
+	if err != nil {
+		return nil, fmt.Errorf("collection %s: %w", coll.Name, err)
+	}
+
+	out.Schema = Draft
+	out.Title = coll.Name
+
+	if len(g.defs) != 0 {
+		out.Defs = g.defs
+	}
+
+	return out, nil
+}
+
+type generator struct {
+	defs map[string]*Schema
+}
+
+func (g *generator) object(fields []metadata.ObjectField) (*Schema, error) {
+	out := &Schema{
+		Type:                 "object",
+		Properties:           make(map[string]*Schema, len(fields)),
+		AdditionalProperties: false,
+	}
+
+	for _, field := range fields {
+		schema, err := g.typ(field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+
+		out.Properties[field.Name] = schema
+
+		if field.Required {
+			out.Required = append(out.Required, field.Name)
+		}
+	}
+
+	return out, nil
+}
+
+func (g *generator) typ(t metadata.Type) (*Schema, error) {
+	switch t.Kind {
+	case "primitive":
+		primitive, _, err := t.Primitive()
+		if err != nil {
+			return nil, err
+		}
+
+		switch primitive.Value {
+		case metadata.PrimitiveTypeString, metadata.PrimitiveTypeNumber, metadata.PrimitiveTypeBoolean:
+			return &Schema{Type: string(primitive.Value)}, nil
+		case metadata.PrimitiveTypeBytes:
+			return &Schema{Type: "string", ContentEncoding: "base64"}, nil
+		default:
+			return nil, fmt.Errorf("unknown primitive type %q", primitive.Value)
+		}
+	case "publickey":
+		g.defs[PublicKeyDef] = publicKey()
+
+		return ref(PublicKeyDef), nil
+	case "record":
+		g.defs[ReferenceDef] = reference("")
+
+		return ref(ReferenceDef), nil
+	case "foreignrecord":
+		rec, _, err := t.ForeignRecord()
+		if err != nil {
+			return nil, err
+		}
+
+		name := rec.Collection + ReferenceDef
+		g.defs[name] = reference(rec.Collection)
+
+		return ref(name), nil
+	case "array":
+		array, _, err := t.Array()
+		if err != nil {
+			return nil, err
+		}
+
+		items, err := g.typ(array.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Schema{Type: "array", Items: items}, nil
+	case "map":
+		mp, _, err := t.Map()
+		if err != nil {
+			return nil, err
+		}
+
+		out := &Schema{Type: "object"}
+
+		key, ok, err := mp.Key.Primitive()
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case ok && key.Value == metadata.PrimitiveTypeString:
+		case ok && key.Value == metadata.PrimitiveTypeNumber:
+			out.PropertyNames = &Schema{Pattern: numberPattern}
+		default:
+			return nil, fmt.Errorf("invalid map key type %s", mp.Key.Kind)
+		}
+
+		if out.AdditionalProperties, err = g.typ(mp.Value); err != nil {
+			return nil, err
+		}
+
+		return out, nil
+	case "object":
+		obj, _, err := t.Object()
+		if err != nil {
+			return nil, err
+		}
+
+		return g.object(obj.Fields)
+	default:
+		return nil, fmt.Errorf("unknown type kind %q", t.Kind)
+	}
+}
+
+func ref(def string) *Schema { return &Schema{Ref: "#/$defs/" + def} }
+
+// reference returns the schema of a reference to a record of the collection,
+// or of any collection if it is empty.
+func reference(collection string) *Schema {
+	id := &Schema{Type: "string"}
+	if collection != "" {
+		id.Pattern = "(^|/)" + regexp.QuoteMeta(collection) + "$"
+	}
+
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"collectionId": id,
+			"id":           {Type: "string"},
+		},
+		Required:             []string{"collectionId", "id"},
+		AdditionalProperties: false,
+	}
+}
+
+func publicKey() *Schema {
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"kty": {Type: "string", Enum: []string{"EC"}},
+			"crv": {Type: "string", Enum: []string{"secp256k1"}},
+			"alg": {Type: "string", Enum: []string{"ES256K"}},
+			"use": {Type: "string", Enum: []string{"sig"}},
+			"x":   {Type: "string"},
+			"y":   {Type: "string"},
+		},
+		Required:             []string{"kty", "crv", "alg", "use", "x", "y"},
+		AdditionalProperties: false,
+	}
+}
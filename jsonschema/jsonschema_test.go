@@ -0,0 +1,166 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/durudex/go-polylang/compiler"
+	"github.com/durudex/go-polylang/internal/jsontest"
+	"github.com/durudex/go-polylang/jsonschema"
+	"github.com/durudex/go-polylang/metadata"
+	"github.com/durudex/go-polylang/parser"
+)
+
+func TestGenerate(t *testing.T) {
+	prog, err := parser.Parse("fixtures/users.polylang")
+	if err != nil {
+		t.Fatal("error: parsing polylang code: ", err)
+	}
+
+	root, err := compiler.Compile(prog, "ns")
+	if err != nil {
+		t.Fatal("error: compiling program: ", err)
+	}
+
+	want, err := os.ReadFile("fixtures/user.schema.json")
+	if err != nil {
+		t.Fatal("error: reading fixtures file: ", err)
+	}
+
+	for name, node := range map[string]any{"Program": prog, "Metadata": root} {
+		t.Run(name, func(t *testing.T) {
+			schemas, err := jsonschema.Generate(node)
+			if err != nil {
+				t.Fatal("error: generating schemas: ", err)
+			}
+
+			if len(schemas) != 2 || schemas[1].Title != "Account" {
+				t.Fatal("error: expected a schema for every collection")
+			}
+
+			got, err := json.Marshal(schemas[0])
+			if err != nil {
+				t.Fatal("error: marshal json: ", err)
+			}
+
+			if !reflect.DeepEqual(jsontest.Normalize(t, got), jsontest.Normalize(t, want)) {
+				t.Fatalf("error: schema does not match:\n%s", got)
+			}
+		})
+	}
+}
+
+var CollectionTests = map[string]struct {
+	data string
+	want string
+}{
+	"Array": {
+		data: `{"kind":"property","name":"a","type":{"kind":"array","value":{"kind":"object","fields":[` +
+			`{"name":"b","type":{"kind":"primitive","value":"number"},"required":false}]}},"directives":[],"required":false}`,
+		want: `{"$schema":"https://json-schema.org/draft/2020-12/schema","title":"T","type":"object","properties":{` +
+			`"a":{"type":"array","items":{"type":"object","properties":{"b":{"type":"number"}},"additionalProperties":false}}},` +
+			`"additionalProperties":false}`,
+	},
+	"Map": {
+		data: `{"kind":"property","name":"a","type":{"kind":"map","key":{"kind":"primitive","value":"string"},` +
+			`"value":{"kind":"foreignrecord","collection":"T"}},"directives":[],"required":true}`,
+		want: `{"$schema":"https://json-schema.org/draft/2020-12/schema","title":"T","type":"object","properties":{` +
+			`"a":{"type":"object","additionalProperties":{"$ref":"#/$defs/TReference"}}},` +
+			`"required":["a"],"additionalProperties":false,"$defs":{"TReference":{"type":"object","properties":{` +
+			`"collectionId":{"type":"string","pattern":"(^|/)T$"},"id":{"type":"string"}},` +
+			`"required":["collectionId","id"],"additionalProperties":false}}}`,
+	},
+}
+
+func TestCollection(t *testing.T) {
+	for name, test := range CollectionTests {
+		t.Run(name, func(t *testing.T) {
+			var attr metadata.CollectionAttribute
+			if err := json.Unmarshal([]byte(test.data), &attr); err != nil {
+				t.Fatal("error: unmarshal json: ", err)
+			}
+
+			schema, err := jsonschema.Collection(&metadata.Collection{
+				Name: "T", Attributes: []metadata.CollectionAttribute{attr},
+			})
+			if err != nil {
+				t.Fatal("error: generating schema: ", err)
+			}
+
+			got, err := json.Marshal(schema)
+			if err != nil {
+				t.Fatal("error: marshal json: ", err)
+			}
+
+			if !reflect.DeepEqual(jsontest.Normalize(t, got), jsontest.Normalize(t, []byte(test.want))) {
+				t.Fatalf("error: schema does not match:\n%s", got)
+			}
+		})
+	}
+}
+
+func TestCollection_Error(t *testing.T) {
+	var attr metadata.CollectionAttribute
+
+	data := `{"kind":"property","name":"a","type":{"kind":"map","key":{"kind":"publickey"},` +
+		`"value":{"kind":"primitive","value":"string"}},"directives":[],"required":true}`
+	if err := json.Unmarshal([]byte(data), &attr); err != nil {
+		t.Fatal("error: unmarshal json: ", err)
+	}
+
+	if _, err := jsonschema.Collection(&metadata.Collection{
+		Name: "T", Attributes: []metadata.CollectionAttribute{attr},
+	}); err == nil {
+		t.Fatal("error: expected an error for an invalid map key")
+	}
+}
+
+func TestGenerate_Reference(t *testing.T) {
+	prog, err := parser.Must.ParseString("", "collection T { a: T; }")
+	if err != nil {
+		t.Fatal("error: parsing polylang code: ", err)
+	}
+
+	schemas, err := jsonschema.Generate(prog)
+	if err != nil {
+		t.Fatal("error: generating schemas: ", err)
+	}
+
+	pattern := regexp.MustCompile(schemas[0].Defs["TReference"].Properties["collectionId"].Pattern)
+
+	for id, want := range map[string]bool{"T": true, "ns/T": true, "a/b/T": true, "nsT": false, "T/ns": false} {
+		if pattern.MatchString(id) != want {
+			t.Fatal("error: unexpected match of collection id: ", id)
+		}
+	}
+}
+
+func TestGenerate_NumberKeys(t *testing.T) {
+	prog, err := parser.Must.ParseString("", "collection T { a: map<number, string>; }")
+	if err != nil {
+		t.Fatal("error: parsing polylang code: ", err)
+	}
+
+	schemas, err := jsonschema.Generate(prog)
+	if err != nil {
+		t.Fatal("error: generating schemas: ", err)
+	}
+
+	pattern := regexp.MustCompile(schemas[0].Properties["a"].PropertyNames.Pattern)
+
+	for key, want := range map[string]bool{"1": true, "-2.5": true, "1e10": true, "01": false, "a": false} {
+		if pattern.MatchString(key) != want {
+			t.Fatal("error: unexpected match of key: ", key)
+		}
+	}
+}
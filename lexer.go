@@ -9,13 +9,25 @@ package polylang
 
 import "github.com/alecthomas/participle/v2/lexer"
 
-var Lexer = lexer.MustStateful(lexer.Rules{
-	"Root": []lexer.Rule{
-		{Name: "comment", Pattern: `//.*|\/\*[\s\S]*?\*\/`},
-		{Name: "whitespace", Pattern: `\s+`},
-		{Name: "Ident", Pattern: `[a-zA-Z_.][a-zA-Z0-9_.]*`},
-		{Name: "String", Pattern: `'[^']*'|"[^"]*"`},
-		{Name: "Number", Pattern: `[-+]?[.0-9]+\b`},
-		{Name: "Punct", Pattern: `\[|]|[?:;@(),{}!~*/%+-<>&=^\|]`},
-	},
-})
+var Lexer = lexer.MustStateful(rules("comment"))
+
+// CommentLexer tokenizes the same way as Lexer, but keeps the comments as
+// Comment tokens instead of dropping them, so that tools like the formatter
+// can put them back into the source.
+var CommentLexer = lexer.MustStateful(rules("Comment"))
+
+func rules(comment string) lexer.Rules {
+	return lexer.Rules{
+		"Root": []lexer.Rule{
+			{Name: comment, Pattern: `//.*|\/\*[\s\S]*?\*\/`},
+			{Name: "whitespace", Pattern: `\s+`},
+			{Name: "Ident", Pattern: `[a-zA-Z_][a-zA-Z0-9_]*`},
+			{Name: "String", Pattern: `'(?:\\.|[^'\\\n])*'|"(?:\\.|[^"\\\n])*"`},
+			// Strings that are not closed on their line are still tokens, so
+			// that the parser can report them instead of invalid input.
+			{Name: "Unterminated", Pattern: `'(?:\\.|[^'\\\n])*\\?|"(?:\\.|[^"\\\n])*\\?`},
+			{Name: "Number", Pattern: `(?:0[xX][0-9a-fA-F](?:_?[0-9a-fA-F])*|(?:[0-9](?:_?[0-9])*)?\.?[0-9](?:_?[0-9])*(?:[eE][+-]?[0-9](?:_?[0-9])*)?)\b`},
+			{Name: "Punct", Pattern: `\[|]|[?:;@(),.{}!~*/%+\-<>&=^|]`},
+		},
+	}
+}
@@ -10,8 +10,8 @@ package metadata
 import "encoding/json"
 
 type Directive struct {
-	Name      string            `json:"name"`
-	Arguments DirectiveArgument `json:"arguments"`
+	Name      string              `json:"name"`
+	Arguments []DirectiveArgument `json:"arguments"`
 }
 
 func (ca CollectionAttribute) Directive() (*Directive, bool, error) {
@@ -17,16 +17,18 @@ import (
 
 func TestCollectionAttribute_Directive(t *testing.T) {
 	raw := []byte("{\"kind\":\"directive\",\"name\":\"call\"," +
-		"\"arguments\":{\"kind\":\"fieldreference\",\"path\":" +
-		"[\"owner\"]}}",
+		"\"arguments\":[{\"kind\":\"fieldreference\",\"path\":" +
+		"[\"owner\"]}]}",
 	)
 	want := &metadata.Directive{
 		Name: "call",
-		Arguments: metadata.DirectiveArgument{
-			Kind: "fieldreference",
-			Value: json.RawMessage(
-				"{\"kind\":\"fieldreference\",\"path\":[\"owner\"]}",
-			),
+		Arguments: []metadata.DirectiveArgument{
+			{
+				Kind: "fieldreference",
+				Value: json.RawMessage(
+					"{\"kind\":\"fieldreference\",\"path\":[\"owner\"]}",
+				),
+			},
 		},
 	}
 	ca := metadata.CollectionAttribute{}
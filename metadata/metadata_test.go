@@ -17,11 +17,11 @@ import (
 
 var ParseTests = map[string]struct {
 	data []byte
-	want *metadata.Root
+	want metadata.Root
 }{
 	"OK": {
 		data: []byte("[{\"kind\":\"collection\",\"value\":{}}]"),
-		want: &metadata.Root{
+		want: metadata.Root{
 			{
 				Kind:  "collection",
 				Value: json.RawMessage("{\"kind\":\"collection\",\"value\":{}}"),
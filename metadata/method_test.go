@@ -44,16 +44,18 @@ func TestCollectionAttribute_Method(t *testing.T) {
 
 func TestMethodAttribute_Directive(t *testing.T) {
 	raw := []byte("{\"kind\":\"directive\",\"name\":\"call\"," +
-		"\"arguments\":{\"kind\":\"fieldreference\",\"path\":" +
-		"[\"owner\"]}}",
+		"\"arguments\":[{\"kind\":\"fieldreference\",\"path\":" +
+		"[\"owner\"]}]}",
 	)
 	want := &metadata.Directive{
 		Name: "call",
-		Arguments: metadata.DirectiveArgument{
-			Kind: "fieldreference",
-			Value: json.RawMessage(
-				"{\"kind\":\"fieldreference\",\"path\":[\"owner\"]}",
-			),
+		Arguments: []metadata.DirectiveArgument{
+			{
+				Kind: "fieldreference",
+				Value: json.RawMessage(
+					"{\"kind\":\"fieldreference\",\"path\":[\"owner\"]}",
+				),
+			},
 		},
 	}
 	ma := metadata.MethodAttribute{}
@@ -0,0 +1,169 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package parser
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/durudex/go-polylang"
+	"github.com/durudex/go-polylang/ast"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+// commentGroup is a comment group with the code tokens around it.
+type commentGroup struct {
+	*ast.CommentGroup
+
+	// prev and next are the indexes of the code tokens before and after the
+	// group, and trailing is set when the group starts on the line of prev.
+	prev, next int
+	trailing   bool
+	endLine    int
+}
+
+// comments holds the comment groups of a file by the code tokens they are
+// attached to.
+type comments struct {
+	code []lexer.Token
+	// docs are the groups ending on the line before the code token, and lines
+	// are the groups placed after the code token on its line.
+	docs, lines map[int]*ast.CommentGroup
+}
+
+// parseComments lexes the comments of the source and attaches them to the
+// program. Characters that cannot be tokenized are skipped and returned as
+// errors.
+func parseComments(prog *ast.Program, filename string, src []byte) ErrorList {
+	tokens, errors := lex(polylang.CommentLexer, filename, src)
+
+	c := newComments(prog, tokens)
+
+	ast.Inspect(prog, func(node any) bool {
+		switch n := node.(type) {
+		case *ast.Collection:
+			n.Doc = c.doc(n.Pos)
+		case *ast.Item:
+			doc, line := c.doc(n.Pos), c.line(n.EndPos)
+
+			switch {
+			case n.Function != nil:
+				n.Function.Doc = doc
+			case n.Field != nil:
+				n.Field.Doc, n.Field.Comment = doc, line
+			case n.Index != nil:
+				n.Index.Doc, n.Index.Comment = doc, line
+			}
+		case *ast.Function:
+			if n.Doc == nil {
+				n.Doc = c.doc(n.Pos)
+			}
+		case *ast.Field:
+			if n.Doc == nil && n.Comment == nil {
+				n.Doc, n.Comment = c.doc(n.Pos), c.line(n.EndPos)
+			}
+		}
+
+		return true
+	})
+
+	return errors
+}
+
+// newComments groups the comments of the tokens and keeps them in the
+// program.
+func newComments(prog *ast.Program, tokens []lexer.Token) *comments {
+	comment := polylang.CommentLexer.Symbols()["Comment"]
+
+	c := &comments{docs: map[int]*ast.CommentGroup{}, lines: map[int]*ast.CommentGroup{}}
+
+	var (
+		groups []*commentGroup
+		group  *commentGroup
+	)
+
+	for _, token := range tokens {
+		if token.Type != comment {
+			c.code = append(c.code, token)
+			group = nil
+
+			continue
+		}
+
+		trailing := len(c.code) != 0 && c.code[len(c.code)-1].Pos.Line == token.Pos.Line
+
+		if group == nil || group.trailing || trailing || token.Pos.Line > group.endLine+1 {
+			group = &commentGroup{
+				CommentGroup: &ast.CommentGroup{},
+				prev:         len(c.code) - 1,
+				next:         len(c.code),
+				trailing:     trailing,
+			}
+			groups = append(groups, group)
+		}
+
+		group.List = append(group.List, &ast.Comment{
+			Pos:    token.Pos,
+			EndPos: end(token),
+			Text:   token.Value,
+		})
+		group.endLine = end(token).Line
+	}
+
+	for _, group := range groups {
+		prog.Comments = append(prog.Comments, group.CommentGroup)
+
+		switch {
+		case group.trailing:
+			c.lines[group.prev] = group.CommentGroup
+		case group.next < len(c.code) && c.code[group.next].Pos.Line == group.endLine+1:
+			c.docs[group.next] = group.CommentGroup
+		}
+	}
+
+	return c
+}
+
+// end returns the position after the token.
+func end(token lexer.Token) lexer.Position {
+	pos := token.Pos
+	pos.Offset += len(token.Value)
+
+	if i := strings.LastIndexByte(token.Value, '\n'); i >= 0 {
+		pos.Line += strings.Count(token.Value, "\n")
+		pos.Column = len(token.Value) - i
+	} else {
+		pos.Column += len(token.Value)
+	}
+
+	return pos
+}
+
+// index returns the index of the first code token at or after pos.
+func (c *comments) index(pos lexer.Position) int {
+	return sort.Search(len(c.code), func(i int) bool {
+		return c.code[i].Pos.Offset >= pos.Offset
+	})
+}
+
+// doc returns the doc comment of a node starting at pos.
+func (c *comments) doc(pos lexer.Position) *ast.CommentGroup {
+	return c.docs[c.index(pos)]
+}
+
+// line returns the line comment of a node ending at end, which may follow the
+// semicolon after it.
+func (c *comments) line(end lexer.Position) *ast.CommentGroup {
+	i := c.index(end)
+	if i < len(c.code) && c.code[i].Value == ";" {
+		i++
+	}
+
+	return c.lines[i-1]
+}
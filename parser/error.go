@@ -0,0 +1,110 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+// Error is a syntax error.
+type Error struct {
+	Pos     lexer.Position
+	Message string
+
+	// Unexpected is the token that was found, if it is known.
+	Unexpected string
+	// Expected describes the tokens that were allowed, if they are known.
+	Expected string
+}
+
+func (e *Error) Error() string { return fmt.Sprintf("%s: %s", e.Pos, e.Message) }
+
+var unexpectedMessage = regexp.MustCompile(`^unexpected token "(.*)"(?: \(expected (.*)\))?$`)
+
+// newError converts a parsing or lexing error of participle.
+func newError(err error) *Error {
+	perr, ok := err.(interface {
+		Message() string
+		Position() lexer.Position
+	})
+	if !ok {
+		return &Error{Message: err.Error()}
+	}
+
+	out := &Error{Pos: perr.Position(), Message: perr.Message()}
+
+	if m := unexpectedMessage.FindStringSubmatch(out.Message); m != nil {
+		out.Unexpected, out.Expected = m[1], m[2]
+	}
+
+	return out
+}
+
+// ErrorList is a list of syntax errors, ordered by position within each file.
+type ErrorList []*Error
+
+func (l ErrorList) Len() int      { return len(l) }
+func (l ErrorList) Swap(i, j int) { l[i], l[j] = l[j], l[i] }
+
+func (l ErrorList) Less(i, j int) bool {
+	a, b := l[i].Pos, l[j].Pos
+
+	if a.Filename != b.Filename {
+		return a.Filename < b.Filename
+	}
+
+	return a.Offset < b.Offset
+}
+
+// Sort sorts the list by file and position.
+func (l ErrorList) Sort() { sort.Sort(l) }
+
+// RemoveMultiples sorts the list and keeps only the first error at each
+// position.
+func (l *ErrorList) RemoveMultiples() {
+	sort.Stable(*l)
+
+	var (
+		last lexer.Position
+		i    int
+	)
+
+	for _, e := range *l {
+		if i == 0 || e.Pos != last {
+			last = e.Pos
+			(*l)[i] = e
+			i++
+		}
+	}
+
+	*l = (*l)[:i]
+}
+
+func (l ErrorList) Error() string {
+	switch len(l) {
+	case 0:
+		return "no errors"
+	case 1:
+		return l[0].Error()
+	}
+
+	return fmt.Sprintf("%s (and %d more errors)", l[0], len(l)-1)
+}
+
+// Err returns the list as an error, or nil when it is empty.
+func (l ErrorList) Err() error {
+	if len(l) == 0 {
+		return nil
+	}
+
+	return l
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package parser
+
+import (
+	"bytes"
+	"unicode/utf8"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+// lex tokenizes the source with the lexer definition. A character that cannot
+// be tokenized is recorded as an error and skipped, so that the tokens after
+// it are still returned. The tokens end with the EOF token.
+func lex(def lexer.Definition, filename string, src []byte) ([]lexer.Token, ErrorList) {
+	var (
+		tokens []lexer.Token
+		errors ErrorList
+	)
+
+	base := lexer.Position{Filename: filename, Line: 1, Column: 1}
+
+	for {
+		lex, err := def.Lex(filename, bytes.NewReader(src[base.Offset:]))
+		if err != nil {
+			return append(tokens, lexer.EOFToken(base)), append(errors, newError(err))
+		}
+
+		token, err := lex.Next()
+		for ; err == nil && !token.EOF(); token, err = lex.Next() {
+			token.Pos = shift(base, token.Pos)
+			tokens = append(tokens, token)
+		}
+
+		if err == nil {
+			token.Pos = shift(base, token.Pos)
+
+			return append(tokens, token), errors
+		}
+
+		e := newError(err)
+		e.Pos = shift(base, e.Pos)
+		errors = append(errors, e)
+
+		if e.Pos.Offset >= len(src) {
+			return append(tokens, lexer.EOFToken(e.Pos)), errors
+		}
+
+		_, size := utf8.DecodeRune(src[e.Pos.Offset:])
+
+		base = e.Pos
+		base.Advance(string(src[base.Offset : base.Offset+size]))
+	}
+}
+
+// shift moves a position of a lexer that started at base into the file.
+func shift(base, pos lexer.Position) lexer.Position {
+	if pos.Line == 1 {
+		pos.Column += base.Column - 1
+	}
+
+	pos.Filename = base.Filename
+	pos.Offset += base.Offset
+	pos.Line += base.Line - 1
+
+	return pos
+}
+
+// tokenLexer is a lexer that returns tokens that were already lexed.
+type tokenLexer []lexer.Token
+
+func (l *tokenLexer) Next() (lexer.Token, error) {
+	token := (*l)[0]
+	if len(*l) > 1 {
+		*l = (*l)[1:]
+	}
+
+	return token, nil
+}
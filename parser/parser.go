@@ -17,68 +17,107 @@ import (
 	"github.com/alecthomas/participle/v2"
 )
 
+// Mode controls the behaviour of the parsing functions.
+type Mode uint
+
+const (
+	// AllErrors recovers from syntax errors at item and statement boundaries,
+	// skips characters that cannot be tokenized and reports every error
+	// instead of only the first one.
+	AllErrors Mode = 1 << iota
+	// ParseComments keeps the comments in Program.Comments and attaches doc
+	// and line comments to the nodes they document.
+	ParseComments
+)
+
 var Must = participle.MustBuild[ast.Program](
 	participle.Lexer(polylang.Lexer),
 )
 
-func Parse(path string) (*ast.Program, error) {
+func Parse(path string) (*ast.Program, error) { return ParseMode(path, 0) }
+
+// ParseMode parses a file or every file of a directory. Syntax errors are
+// returned as an ErrorList. With AllErrors, the program built from the code
+// that could be parsed is returned together with the errors.
+func ParseMode(path string, mode Mode) (*ast.Program, error) {
 	info, err := os.Stat(path)
 	if err != nil {
 		return nil, err
 	}
 
 	if info.IsDir() {
-		return ParseDir(path)
+		return ParseDirMode(path, mode)
 	}
 
-	f, err := os.Open(path)
+	src, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
-	defer f.Close()
 
-	return Must.Parse(info.Name(), f)
+	return ParseFile(info.Name(), src, mode)
 }
 
-func ParseDir(path string) (*ast.Program, error) {
-	f, err := os.Open(path)
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
+func ParseDir(path string) (*ast.Program, error) { return ParseDirMode(path, 0) }
 
-	entries, err := f.ReadDir(0)
+// ParseDirMode parses every Polylang file of a directory into a single
+// program. Without AllErrors, it stops at the first file with errors.
+func ParseDirMode(path string, mode Mode) (*ast.Program, error) {
+	entries, err := os.ReadDir(path)
 	if err != nil {
 		return nil, err
 	}
 
-	var ast ast.Program
+	var (
+		prog   ast.Program
+		errors ErrorList
+	)
 
 	for _, entry := range entries {
-		if entry.IsDir() {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".polylang" {
 			continue
 		}
 
 		fp := filepath.Join(path, entry.Name())
 
-		ext := filepath.Ext(fp)
-		if ext != ".polylang" {
-			continue
+		src, err := os.ReadFile(fp)
+		if err != nil {
+			return nil, err
 		}
 
-		f, err := os.Open(fp)
-		if err != nil {
-			return &ast, err
+		fileAst, err := ParseFile(fp, src, mode)
+		if list, ok := err.(ErrorList); ok && mode&AllErrors != 0 {
+			errors = append(errors, list...)
+		} else if err != nil {
+			return nil, err
 		}
-		defer f.Close()
 
-		fileAst, err := Must.Parse(f.Name(), f)
-		if err != nil {
-			return &ast, err
+		if fileAst != nil {
+			prog.Nodes = append(prog.Nodes, fileAst.Nodes...)
+			prog.Comments = append(prog.Comments, fileAst.Comments...)
 		}
+	}
+
+	return &prog, errors.Err()
+}
 
-		ast.Nodes = append(ast.Nodes, fileAst.Nodes...)
+// ParseFile parses the source code of a single file. Syntax errors are
+// returned as an ErrorList.
+func ParseFile(filename string, src []byte, mode Mode) (prog *ast.Program, err error) {
+	if mode&AllErrors != 0 {
+		prog, err = parseRecover(filename, src)
+	} else if prog, err = Must.ParseBytes(filename, src); err != nil {
+		return nil, ErrorList{newError(err)}
+	}
+
+	if prog != nil && mode&ParseComments != 0 {
+		if cerr := parseComments(prog, filename, src); len(cerr) != 0 {
+			list, _ := err.(ErrorList)
+			list = append(list, cerr...)
+			list.RemoveMultiples()
+
+			return prog, list
+		}
 	}
 
-	return &ast, nil
+	return prog, err
 }
@@ -0,0 +1,359 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package parser_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/durudex/go-polylang/parser"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+// brokenCode has a syntax error in an item, a statement and a function header,
+// and a missing semicolon.
+const brokenCode = `collection A {
+    id: string;
+    name: ;
+
+    function f(a: number) {
+        this.a = ;
+        if (a) { a = 1 } else { a = 2; }
+        a = 3;
+    }
+
+    function g( {}
+
+    age: number
+}
+
+function h() { return 1; }
+`
+
+type position struct {
+	Line, Column int
+}
+
+func positions(t *testing.T, err error) []position {
+	var list parser.ErrorList
+	if !errors.As(err, &list) {
+		t.Fatalf("error: expected error list: %v", err)
+	}
+
+	var out []position
+	for _, e := range list {
+		out = append(out, position{e.Pos.Line, e.Pos.Column})
+	}
+
+	return out
+}
+
+func TestParseFile_AllErrors(t *testing.T) {
+	prog, err := parser.ParseFile("a.polylang", []byte(brokenCode), parser.AllErrors)
+
+	want := []position{{3, 11}, {6, 18}, {7, 24}, {11, 17}, {14, 1}}
+	if got := positions(t, err); !reflect.DeepEqual(got, want) {
+		t.Fatalf("error: error positions do not match: %v", got)
+	}
+
+	if len(prog.Nodes) != 2 {
+		t.Fatalf("error: expected 2 nodes, got %d", len(prog.Nodes))
+	}
+
+	coll := prog.Nodes[0].Collection
+	if len(coll.Items) != 2 || coll.Items[0].Field.Name != "id" {
+		t.Fatal("error: collection items do not match")
+	}
+
+	if fn := coll.Items[1].Function; fn.Name != "f" || len(fn.Statements) != 1 {
+		t.Fatal("error: function does not match")
+	}
+
+	if prog.Nodes[1].Function.Name != "h" {
+		t.Fatal("error: function outside collection does not match")
+	}
+}
+
+func TestParseFile_Error(t *testing.T) {
+	prog, err := parser.ParseFile("a.polylang", []byte(brokenCode), 0)
+	if prog != nil {
+		t.Fatal("error: expected no program")
+	}
+
+	if got := positions(t, err); !reflect.DeepEqual(got, []position{{3, 11}}) {
+		t.Fatalf("error: error positions do not match: %v", got)
+	}
+
+	e := err.(parser.ErrorList)[0]
+	if e.Unexpected != ";" || e.Expected != "Type" || e.Pos.Filename != "a.polylang" {
+		t.Fatalf("error: error does not match: %#v", e)
+	}
+}
+
+func TestParseFile_String(t *testing.T) {
+	tests := map[string]struct {
+		literal string
+		column  int
+		want    string
+	}{
+		"Unterminated":       {literal: `'Durudex; } }`, column: 39, want: "string literal not terminated"},
+		"Trailing Backslash": {literal: "\"Durudex\\\n", column: 39, want: "string literal not terminated"},
+		"Unknown Escape":     {literal: `'a\qb'`, column: 41, want: `unknown escape sequence \q`},
+		"Invalid Escape":     {literal: `'\u0041'`, column: 40, want: `invalid escape sequence, expected \u{...}`},
+		"Invalid Code Point": {literal: `'\u{110000}'`, column: 40, want: `invalid Unicode code point \u{110000}`},
+		"Empty Code Point":   {literal: `'\u{}'`, column: 40, want: `invalid Unicode code point \u{}`},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			code := "collection A { function f() { let x = " + test.literal + "; } }"
+
+			_, err := parser.ParseFile("", []byte(code), 0)
+
+			list, ok := err.(parser.ErrorList)
+			if !ok || len(list) != 1 {
+				t.Fatal("error: unexpected error: ", err)
+			}
+
+			if list[0].Message != test.want || list[0].Pos.Column != test.column {
+				t.Fatalf("error: error does not match: %s", list[0])
+			}
+		})
+	}
+}
+
+var ParseFileErrorTests = map[string]struct {
+	code string
+	want []position
+}{
+	"OK": {
+		code: "collection A { id: string; function f() { return 1; } }",
+	},
+	"EOF": {
+		code: "collection A { id: string;",
+		want: []position{{1, 27}},
+	},
+	"Lexer": {
+		code: "collection A { id: string; # }",
+		want: []position{{1, 28}},
+	},
+	"Characters": {
+		code: "collection A {\n    id: string; #\n    function f() { let x = 1 ¤+ 2; }\n}",
+		want: []position{{2, 17}, {3, 30}},
+	},
+	"Unterminated": {
+		code: "collection A { function f() { let x = 'abc; } }",
+		want: []position{{1, 39}, {1, 48}},
+	},
+	"Header": {
+		code: "collection { id: string; } function f() {}",
+		want: []position{{1, 12}},
+	},
+	"Brace": {
+		code: "} function f() {}",
+		want: []position{{1, 1}},
+	},
+}
+
+func TestParseFile_Recover(t *testing.T) {
+	for name, test := range ParseFileErrorTests {
+		t.Run(name, func(t *testing.T) {
+			_, err := parser.ParseFile("", []byte(test.code), parser.AllErrors)
+			if test.want == nil {
+				if err != nil {
+					t.Fatal("error: unexpected error: ", err)
+				}
+
+				return
+			}
+
+			if got := positions(t, err); !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("error: error positions do not match: %v", got)
+			}
+		})
+	}
+}
+
+func TestParseFile_Lexer(t *testing.T) {
+	code := "// A doc.\ncollection A { id: string; # name: string; }"
+
+	prog, err := parser.ParseFile("", []byte(code), parser.AllErrors|parser.ParseComments)
+
+	if got := positions(t, err); !reflect.DeepEqual(got, []position{{2, 28}}) {
+		t.Fatalf("error: error positions do not match: %v", got)
+	}
+
+	if prog == nil || len(prog.Nodes) != 1 {
+		t.Fatal("error: expected the program that could be parsed")
+	}
+
+	coll := prog.Nodes[0].Collection
+	if len(coll.Items) != 2 || coll.Items[1].Field.Name != "name" || coll.Doc.Text() != "A doc.\n" {
+		t.Fatal("error: collection does not match")
+	}
+}
+
+func TestParseDirMode(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"a.polylang": "collection A { id: ; }",
+		"b.polylang": "collection B { id: string; }",
+		"c.polylang": "function c() { let x = ; }",
+		"d.txt":      "not polylang",
+	}
+
+	for name, code := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(code), 0o600); err != nil {
+			t.Fatal("error: writing file: ", err)
+		}
+	}
+
+	prog, err := parser.ParseDirMode(dir, parser.AllErrors)
+
+	var list parser.ErrorList
+	if !errors.As(err, &list) || len(list) != 2 {
+		t.Fatalf("error: expected 2 errors: %v", err)
+	}
+
+	if list[0].Pos.Filename != filepath.Join(dir, "a.polylang") ||
+		list[1].Pos.Filename != filepath.Join(dir, "c.polylang") {
+		t.Fatalf("error: error files do not match: %v", list)
+	}
+
+	if len(prog.Nodes) != 3 {
+		t.Fatalf("error: expected 3 nodes, got %d", len(prog.Nodes))
+	}
+
+	if prog, err := parser.ParseDir(dir); prog != nil || err == nil {
+		t.Fatal("error: expected error without program")
+	}
+}
+
+func TestParseDirMode_Comments(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"a.polylang": "// A doc.\ncollection A { id: string; }",
+		"b.polylang": "collection B { id: string; } // B line.\n// end of b",
+	}
+
+	for name, code := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(code), 0o600); err != nil {
+			t.Fatal("error: writing file: ", err)
+		}
+	}
+
+	prog, err := parser.ParseDirMode(dir, parser.ParseComments)
+	if err != nil {
+		t.Fatal("error: parsing directory: ", err)
+	}
+
+	var got []string
+	for _, group := range prog.Comments {
+		got = append(got, group.Text())
+	}
+
+	want := []string{"A doc.\n", "B line.\n", "end of b\n"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("error: comments do not match: %q", got)
+	}
+}
+
+const commentedCode = `// Package doc.
+
+// Users doc.
+@public
+collection Users {
+    // id doc.
+    id: string; // id line.
+    info: {
+        // name doc.
+        name: string; // name line.
+    };
+
+    // index doc.
+    @index(id); // index line.
+
+    // f doc.
+    @read
+    function f() {
+        // inside.
+        return 1;
+    }
+}
+
+// g doc.
+function g() {}
+`
+
+func TestParseFile_Comments(t *testing.T) {
+	prog, err := parser.ParseFile("", []byte(commentedCode), parser.ParseComments)
+	if err != nil {
+		t.Fatal("error: parsing polylang code: ", err)
+	}
+
+	if len(prog.Comments) != 11 {
+		t.Fatalf("error: expected 11 comment groups, got %d", len(prog.Comments))
+	}
+
+	coll := prog.Nodes[0].Collection
+	name := coll.Items[1].Field.Type.Object[0]
+
+	got := []string{
+		coll.Doc.Text(),
+		coll.Items[0].Field.Doc.Text(), coll.Items[0].Field.Comment.Text(),
+		coll.Items[1].Field.Doc.Text(),
+		name.Doc.Text(), name.Comment.Text(),
+		coll.Items[2].Index.Doc.Text(), coll.Items[2].Index.Comment.Text(),
+		coll.Items[3].Function.Doc.Text(),
+		prog.Nodes[1].Function.Doc.Text(),
+	}
+
+	want := []string{
+		"Users doc.\n",
+		"id doc.\n", "id line.\n",
+		"",
+		"name doc.\n", "name line.\n",
+		"index doc.\n", "index line.\n",
+		"f doc.\n",
+		"g doc.\n",
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("error: comments do not match: %q", got)
+	}
+}
+
+func TestErrorList(t *testing.T) {
+	list := parser.ErrorList{
+		{Pos: lexer.Position{Filename: "a", Offset: 2, Line: 1, Column: 3}, Message: "b"},
+		{Pos: lexer.Position{Filename: "a", Offset: 1, Line: 1, Column: 2}, Message: "a"},
+	}
+
+	list.Sort()
+
+	if want := "a:1:2: a (and 1 more errors)"; list.Error() != want {
+		t.Fatalf("error: message does not match: %s", list.Error())
+	}
+
+	list = append(list, &parser.Error{Pos: list[0].Pos, Message: "c"})
+	list.RemoveMultiples()
+
+	if len(list) != 2 || list[0].Message != "a" || list[1].Message != "b" {
+		t.Fatalf("error: multiples are not removed: %v", list)
+	}
+
+	if parser.ErrorList(nil).Err() != nil {
+		t.Fatal("error: empty list is an error")
+	}
+}
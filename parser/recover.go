@@ -0,0 +1,306 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package parser
+
+import (
+	"github.com/durudex/go-polylang"
+	"github.com/durudex/go-polylang/ast"
+
+	"github.com/alecthomas/participle/v2"
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+// The recovering parser parses the parts of a program that are delimited by
+// braces itself, and leaves everything between them to these parsers.
+type (
+	decorators struct {
+		Decorators []*ast.Decorator `parser:"@@*"`
+	}
+
+	collectionHeader struct {
+		Pos lexer.Position
+
+		Decorators []*ast.Decorator `parser:"@@*"`
+		Name       string           `parser:"'collection' @Ident '{'"`
+	}
+
+	functionHeader struct {
+		Pos lexer.Position
+
+		Name       string       `parser:"( 'function' )? @Ident '('"`
+		Parameters []*ast.Field `parser:"( @@ ( ',' @@ )* )? ')'"`
+		ReturnType ast.Type     `parser:"( ':' @@ )? '{'"`
+	}
+)
+
+var (
+	decoratorsParser       = build[decorators]()
+	collectionHeaderParser = build[collectionHeader]()
+	functionHeaderParser   = build[functionHeader]()
+	itemParser             = build[ast.Item]()
+	statementParser        = build[ast.Statement]()
+)
+
+func build[G any]() *participle.Parser[G] {
+	return participle.MustBuild[G](participle.Lexer(polylang.Lexer))
+}
+
+type recoverer struct {
+	lex    *lexer.PeekingLexer
+	errors ErrorList
+}
+
+// parseRecover parses a file, skipping the items and statements with syntax
+// errors.
+func parseRecover(filename string, src []byte) (*ast.Program, error) {
+	tokens, errors := lex(polylang.Lexer, filename, src)
+
+	peeker, err := lexer.Upgrade((*tokenLexer)(&tokens))
+	if err != nil {
+		return nil, append(errors, newError(err))
+	}
+
+	r := &recoverer{lex: peeker, errors: errors}
+	prog := r.program()
+
+	r.errors.RemoveMultiples()
+
+	return prog, r.errors.Err()
+}
+
+// parse parses the next part of the code with the parser. On failure the error
+// is recorded and nothing is consumed.
+func parse[G any](r *recoverer, parser *participle.Parser[G]) *G {
+	lex := r.lex.Clone()
+
+	v, err := parser.ParseFromLexer(lex, participle.AllowTrailing(true))
+	if err != nil {
+		r.errors = append(r.errors, newError(err))
+
+		return nil
+	}
+
+	*r.lex = *lex
+
+	return v
+}
+
+func (r *recoverer) program() *ast.Program {
+	prog := &ast.Program{Pos: r.lex.Peek().Pos}
+
+	for !r.lex.Peek().EOF() {
+		start := r.lex.Cursor()
+
+		if node := r.node(); node != nil {
+			prog.Nodes = append(prog.Nodes, node)
+		}
+
+		if r.lex.Cursor() == start {
+			r.lex.Next()
+		}
+	}
+
+	prog.EndPos = r.lex.Peek().Pos
+
+	return prog
+}
+
+func (r *recoverer) node() *ast.Node {
+	pos := r.lex.Peek().Pos
+
+	if r.next() == "collection" {
+		if coll := r.collection(); coll != nil {
+			return &ast.Node{Pos: pos, EndPos: coll.EndPos, Collection: coll}
+		}
+
+		return nil
+	}
+
+	if fn := r.function(); fn != nil {
+		return &ast.Node{Pos: pos, EndPos: fn.EndPos, Function: fn}
+	}
+
+	return nil
+}
+
+// next returns the value of the next token after the decorators.
+func (r *recoverer) next() string {
+	lex := r.lex.Clone()
+
+	if _, err := decoratorsParser.ParseFromLexer(lex, participle.AllowTrailing(true)); err != nil {
+		return ""
+	}
+
+	return lex.Peek().Value
+}
+
+func (r *recoverer) collection() *ast.Collection {
+	header := parse(r, collectionHeaderParser)
+	if header == nil {
+		r.skip()
+
+		return nil
+	}
+
+	coll := &ast.Collection{Pos: header.Pos, Decorators: header.Decorators, Name: header.Name}
+
+	for !r.closing() {
+		start := r.lex.Cursor()
+
+		if item := r.item(); item != nil {
+			coll.Items = append(coll.Items, item)
+		}
+
+		if r.lex.Cursor() == start {
+			r.lex.Next()
+		}
+	}
+
+	coll.EndPos = r.close()
+
+	return coll
+}
+
+func (r *recoverer) item() *ast.Item {
+	pos := r.lex.Peek().Pos
+
+	if !r.isFunction() {
+		item := parse(r, itemParser)
+		if item == nil {
+			r.skip()
+		}
+
+		return item
+	}
+
+	decorators := parse(r, decoratorsParser)
+	if decorators == nil {
+		r.skip()
+
+		return nil
+	}
+
+	fn := r.function()
+	if fn == nil {
+		return nil
+	}
+
+	return &ast.Item{Pos: pos, EndPos: fn.EndPos, Decorators: decorators.Decorators, Function: fn}
+}
+
+// isFunction reports whether the next item is a function, which is either
+// introduced by the keyword or a name followed by parameters.
+func (r *recoverer) isFunction() bool {
+	lex := r.lex.Clone()
+
+	if _, err := decoratorsParser.ParseFromLexer(lex, participle.AllowTrailing(true)); err != nil {
+		return false
+	}
+
+	if token := lex.Next(); token.Value != "function" && lex.Peek().Value != "(" {
+		return false
+	}
+
+	return true
+}
+
+func (r *recoverer) function() *ast.Function {
+	header := parse(r, functionHeaderParser)
+	if header == nil {
+		r.skip()
+
+		return nil
+	}
+
+	fn := &ast.Function{
+		Pos:        header.Pos,
+		Name:       header.Name,
+		Parameters: header.Parameters,
+		ReturnType: header.ReturnType,
+	}
+
+	for !r.closing() {
+		start := r.lex.Cursor()
+
+		if stmt := parse(r, statementParser); stmt != nil {
+			fn.Statements = append(fn.Statements, stmt)
+		} else {
+			r.skip()
+		}
+
+		if r.lex.Cursor() == start {
+			r.lex.Next()
+		}
+	}
+
+	fn.EndPos = r.close()
+
+	return fn
+}
+
+// closing reports whether the enclosing block ends at the next token.
+func (r *recoverer) closing() bool {
+	token := r.lex.Peek()
+
+	return token.EOF() || token.Value == "}"
+}
+
+// close consumes the closing brace of a block and returns the position after
+// it. A missing brace is recorded as an error.
+func (r *recoverer) close() lexer.Position {
+	token := r.lex.Peek()
+
+	if token.EOF() {
+		r.errors = append(r.errors, &Error{
+			Pos:        token.Pos,
+			Message:    `unexpected end of file (expected "}")`,
+			Unexpected: token.String(),
+			Expected:   `"}"`,
+		})
+	} else {
+		r.lex.Next()
+	}
+
+	return r.lex.RawPeek().Pos
+}
+
+// skip drops the tokens of a broken item or statement: up to the next
+// semicolon or the end of the next block, including an else block after it.
+// The closing brace of the enclosing block is never dropped.
+func (r *recoverer) skip() {
+	depth := 0
+
+	for token := r.lex.Peek(); !token.EOF(); token = r.lex.Peek() {
+		switch token.Value {
+		case "{":
+			depth++
+		case "}":
+			if depth == 0 {
+				return
+			}
+
+			if depth--; depth == 0 {
+				r.lex.Next()
+
+				if r.lex.Peek().Value != "else" {
+					return
+				}
+
+				continue
+			}
+		case ";":
+			if depth == 0 {
+				r.lex.Next()
+
+				return
+			}
+		}
+
+		r.lex.Next()
+	}
+}
@@ -0,0 +1,572 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package printer
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/durudex/go-polylang/ast"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+func (p *printer) program(prog *ast.Program) {
+	for i, node := range prog.Nodes {
+		if i != 0 {
+			p.blank()
+		}
+
+		p.leading(node.Pos)
+		p.topLevel(node)
+		p.trailing(node.EndPos)
+	}
+
+	if len(prog.Nodes) != 0 {
+		p.print("\n")
+	}
+
+	p.rest()
+}
+
+func (p *printer) topLevel(node *ast.Node) {
+	switch {
+	case node.Collection != nil:
+		p.collection(node.Collection)
+	case node.Function != nil:
+		p.doc(node.Function.Doc)
+		p.function(node.Function)
+	}
+}
+
+func (p *printer) decorators(decorators []*ast.Decorator) {
+	for _, decorator := range decorators {
+		p.decorator(decorator)
+		p.newline()
+	}
+}
+
+func (p *printer) decorator(decorator *ast.Decorator) {
+	p.print("@", decorator.Name.String())
+
+	if len(decorator.Arguments) != 0 {
+		p.print("(")
+
+		for i, arg := range decorator.Arguments {
+			if i != 0 {
+				p.print(", ")
+			}
+
+			p.print(string(arg))
+		}
+
+		p.print(")")
+	}
+}
+
+func (p *printer) collection(coll *ast.Collection) {
+	p.doc(coll.Doc)
+	p.decorators(coll.Decorators)
+	p.print("collection ", coll.Name, " ")
+
+	p.braces(len(coll.Items), coll.EndPos, func(i int) {
+		item := coll.Items[i]
+
+		if i != 0 && separated(coll.Items[i-1], item) {
+			p.blank()
+		} else {
+			p.newline()
+		}
+
+		p.leading(item.Pos)
+		p.item(item)
+		p.trailing(item.EndPos)
+	})
+}
+
+// braces prints n entries between braces, where each entry starts its own
+// line, or an empty pair of braces if there is nothing to put between them.
+func (p *printer) braces(n int, end lexer.Position, entry func(i int)) {
+	p.print("{")
+	p.depth++
+
+	for i := 0; i < n; i++ {
+		entry(i)
+	}
+
+	commented := p.closing(end)
+
+	p.depth--
+
+	if n != 0 || commented {
+		p.newline()
+	}
+
+	p.print("}")
+}
+
+// separated reports whether an empty line is put between two items, which sets
+// apart functions and groups of items of the same kind.
+func separated(prev, item *ast.Item) bool {
+	return item.Function != nil || itemKind(prev) != itemKind(item)
+}
+
+func itemKind(item *ast.Item) int {
+	switch {
+	case item.Function != nil:
+		return 1
+	case item.Field != nil:
+		return 2
+	default:
+		return 3
+	}
+}
+
+func (p *printer) item(item *ast.Item) {
+	switch {
+	case item.Function != nil:
+		p.doc(item.Function.Doc)
+		p.decorators(item.Decorators)
+		p.function(item.Function)
+	case item.Field != nil:
+		p.doc(item.Field.Doc)
+		p.decorators(item.Decorators)
+		p.field(item.Field)
+		p.print(";")
+		p.line(item.Field.Comment)
+	case item.Index != nil:
+		p.doc(item.Index.Doc)
+		p.decorators(item.Decorators)
+		p.index(item.Index)
+		p.print(";")
+		p.line(item.Index.Comment)
+	}
+}
+
+// doc prints the doc comment of a node, each comment on its own line. Comments
+// attached to nodes are not printed when the source tokens are known, as they
+// are printed from the tokens instead.
+func (p *printer) doc(doc *ast.CommentGroup) {
+	if doc == nil || p.source {
+		return
+	}
+
+	for _, comment := range doc.List {
+		p.print(comment.Text)
+		p.newline()
+	}
+}
+
+// line prints the line comment of a node after it.
+func (p *printer) line(comment *ast.CommentGroup) {
+	if comment == nil || p.source {
+		return
+	}
+
+	for _, c := range comment.List {
+		p.print(" ", c.Text)
+	}
+}
+
+func (p *printer) field(field *ast.Field) {
+	p.print(field.Name)
+
+	if field.Optional {
+		p.print("?")
+	}
+
+	p.print(": ")
+	p.typ(&field.Type)
+}
+
+func (p *printer) index(index *ast.Index) {
+	p.print("@index(")
+
+	for i, field := range index.Fields {
+		if i != 0 {
+			p.print(", ")
+		}
+
+		p.indexField(field)
+	}
+
+	p.print(")")
+}
+
+func (p *printer) indexField(field *ast.IndexField) {
+	if field.Order == ast.Asc {
+		p.print(string(field.Name))
+
+		return
+	}
+
+	p.print("[", string(field.Name), ", ", field.Order.String(), "]")
+}
+
+func (p *printer) function(fn *ast.Function) {
+	p.print("function ", fn.Name, "(")
+
+	for i, param := range fn.Parameters {
+		if i != 0 {
+			p.print(", ")
+		}
+
+		p.field(param)
+	}
+
+	p.print(")")
+
+	if !fn.ReturnType.IsZero() {
+		p.print(": ")
+		p.typ(&fn.ReturnType)
+	}
+
+	p.print(" ")
+	p.block(fn.Statements, fn.EndPos)
+}
+
+func (p *printer) typ(t *ast.Type) {
+	switch {
+	case t.Basic != 0:
+		p.print(t.Basic.String())
+
+		if t.Array {
+			p.print("[]")
+		}
+	case t.Map != nil:
+		p.print("map<", t.Map.Key.String(), ", ")
+		p.typ(&t.Map.Value)
+		p.print(">")
+	case t.Object != nil:
+		p.braces(len(t.Object), t.EndPos, func(i int) {
+			field := t.Object[i]
+
+			p.newline()
+			p.leading(field.Pos)
+			p.doc(field.Doc)
+			p.field(field)
+			p.print(";")
+			p.line(field.Comment)
+
+			// The semicolon is not part of the field, so the comments that
+			// trail it end before the next field instead.
+			if i+1 < len(t.Object) {
+				p.trailing(t.Object[i+1].Pos)
+			} else {
+				p.trailing(p.last(t.EndPos).Pos)
+			}
+		})
+	case t.Foreign != "":
+		p.print(t.Foreign)
+	}
+}
+
+func (p *printer) block(statements []*ast.Statement, end lexer.Position) {
+	p.braces(len(statements), end, func(i int) {
+		p.newline()
+		p.leading(statements[i].Pos)
+		p.statement(statements[i])
+		p.trailing(statements[i].EndPos)
+	})
+}
+
+func (p *printer) statement(stmt *ast.Statement) {
+	switch {
+	case stmt.Compound != nil:
+		p.compound(stmt.Compound)
+	case stmt.Simple != nil:
+		p.simple(stmt.Simple)
+	}
+}
+
+func (p *printer) simple(stmt *ast.SimpleStatement) {
+	p.small(stmt.Small)
+	p.print(";")
+}
+
+func (p *printer) small(stmt *ast.SmallStatement) {
+	switch {
+	case stmt.Break:
+		p.print("break")
+	case stmt.Continue:
+		p.print("continue")
+	case stmt.Return != nil && stmt.Return.Value == nil:
+		p.print("return")
+	case stmt.Return != nil:
+		p.print("return ")
+		p.expression(stmt.Return.Value)
+	case stmt.Throw != nil:
+		p.print("throw ")
+		p.expression(stmt.Throw)
+	case stmt.Let != nil:
+		p.let(stmt.Let)
+	case stmt.Expression != nil:
+		p.expression(stmt.Expression)
+	}
+}
+
+func (p *printer) let(let *ast.Let) {
+	p.print("let ", let.Ident, " = ")
+	p.expression(let.Expression)
+}
+
+func (p *printer) compound(stmt *ast.CompoundStatement) {
+	switch {
+	case stmt.If != nil:
+		p.ifStatement(stmt.If)
+	case stmt.While != nil:
+		p.print("while (")
+		p.expression(stmt.While.Condition)
+		p.print(") ")
+		p.block(stmt.While.Statements, stmt.While.EndPos)
+	case stmt.For != nil:
+		p.forStatement(stmt.For)
+	case stmt.Block != nil:
+		p.block(stmt.Block.Statements, stmt.Block.EndPos)
+	}
+}
+
+func (p *printer) ifStatement(stmt *ast.If) {
+	p.print("if (")
+	p.expression(stmt.Condition)
+	p.print(")")
+
+	if stmt.Statement != nil {
+		p.print(" ")
+		p.statementsOrSimple(stmt.Statement)
+	}
+
+	if stmt.Else != nil {
+		p.print(" else ")
+		p.statementsOrSimple(stmt.Else)
+	}
+}
+
+func (p *printer) statementsOrSimple(stmt *ast.StatementsOrSimple) {
+	switch {
+	case stmt.Compound != nil:
+		p.compound(stmt.Compound)
+	case stmt.Simple != nil:
+		p.simple(stmt.Simple)
+	default:
+		p.block(stmt.Statements, stmt.EndPos)
+	}
+}
+
+func (p *printer) forStatement(stmt *ast.For) {
+	p.print("for (")
+
+	switch {
+	case stmt.Initial.Let != nil:
+		p.let(stmt.Initial.Let)
+	case stmt.Initial.Expression != nil:
+		p.expression(stmt.Initial.Expression)
+	}
+
+	p.print("; ")
+	p.expression(stmt.Condition)
+	p.print("; ")
+	p.expression(stmt.Post)
+	p.print(") ")
+	p.block(stmt.Statements, stmt.EndPos)
+}
+
+func (p *printer) expression(expr *ast.Expression) {
+	switch {
+	case expr.Unary != nil:
+		p.print(expr.Unary.Operator.String())
+
+		// Keep two signs apart so that they are not read back as one operator,
+		// such as - -a and - --a, while !!a and ~~a stay together.
+		if op := expr.Unary.Operator.String(); op == "-" || op == "+" {
+			if sign := prefix(expr.Unary.Operand); sign != "" && sign[0] == op[0] {
+				p.print(" ")
+			}
+		}
+
+		p.operand(expr.Unary.Operand, isBinary)
+	case expr.Update != nil && expr.Update.Prefix:
+		p.print(expr.Update.Operator.String())
+		p.operand(expr.Update.Operand, isOperator)
+	case expr.Update != nil:
+		p.operand(expr.Update.Operand, isOperator)
+		p.print(expr.Update.Operator.String())
+	case expr.Binary != nil:
+		p.binary(expr.Binary)
+	case expr.Call != nil:
+		p.operand(expr.Call.Callee, isOperator)
+		p.print("(")
+		p.list(expr.Call.Arguments)
+		p.print(")")
+	case expr.Member != nil:
+		p.operand(expr.Member.Object, isOperator)
+		p.print(".", expr.Member.Property)
+	case expr.Index != nil:
+		p.operand(expr.Index.Object, isOperator)
+		p.print("[")
+		p.expression(expr.Index.Index)
+		p.print("]")
+	case expr.Array != nil:
+		p.print("[")
+		p.list(expr.Array.Elements)
+		p.print("]")
+	case expr.Object != nil:
+		p.object(expr.Object)
+	case expr.Value != nil:
+		p.value(expr.Value)
+	}
+}
+
+func (p *printer) binary(expr *ast.BinaryExpr) {
+	precedence := expr.Operator.Precedence()
+
+	// Parenthesise operands that would otherwise bind differently, which can
+	// only happen in trees that were built by hand rather than parsed.
+	p.operand(expr.Left, func(e *ast.Expression) bool {
+		return e.Binary != nil && (e.Binary.Operator.Precedence() < precedence ||
+			e.Binary.Operator.Precedence() == precedence && expr.Operator.RightAssociative())
+	})
+	p.print(" ", expr.Operator.String(), " ")
+	p.operand(expr.Right, func(e *ast.Expression) bool {
+		return e.Binary != nil && (e.Binary.Operator.Precedence() < precedence ||
+			e.Binary.Operator.Precedence() == precedence && !expr.Operator.RightAssociative())
+	})
+}
+
+func (p *printer) operand(expr *ast.Expression, parenthesise func(*ast.Expression) bool) {
+	if !parenthesise(expr) {
+		p.expression(expr)
+
+		return
+	}
+
+	p.print("(")
+	p.expression(expr)
+	p.print(")")
+}
+
+// prefix returns the operator that an expression is printed with in front of
+// it, if any.
+func prefix(expr *ast.Expression) string {
+	switch {
+	case expr.Unary != nil:
+		return expr.Unary.Operator.String()
+	case expr.Update != nil && expr.Update.Prefix:
+		return expr.Update.Operator.String()
+	default:
+		return ""
+	}
+}
+
+func isBinary(expr *ast.Expression) bool { return expr.Binary != nil }
+
+func isOperator(expr *ast.Expression) bool {
+	return expr.Binary != nil || expr.Unary != nil || expr.Update != nil
+}
+
+func (p *printer) list(list []*ast.Expression) {
+	for i, expr := range list {
+		if i != 0 {
+			p.print(", ")
+		}
+
+		p.expression(expr)
+	}
+}
+
+func (p *printer) object(object *ast.ObjectLit) {
+	if len(object.Properties) == 0 {
+		p.print("{}")
+
+		return
+	}
+
+	p.print("{ ")
+
+	for i, property := range object.Properties {
+		if i != 0 {
+			p.print(", ")
+		}
+
+		if property.Raw != "" {
+			p.print(property.Raw, ": ")
+		} else {
+			p.print(key(property.Key), ": ")
+		}
+		p.expression(property.Value)
+	}
+
+	p.print(" }")
+}
+
+// key returns the name of a property that was built by hand as an identifier
+// if it is one, or else as a single quoted string literal.
+func key(name string) string {
+	ident := name != "" && !unicode.IsDigit(rune(name[0]))
+
+	for _, r := range name {
+		if r != '_' && !(r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r))) {
+			ident = false
+		}
+	}
+
+	if ident {
+		return name
+	}
+
+	return quote(name)
+}
+
+// quote returns a single quoted string literal of the value.
+func quote(value string) string {
+	var b strings.Builder
+
+	b.WriteByte('\'')
+
+	for _, r := range value {
+		switch {
+		case r == '\'' || r == '\\':
+			b.WriteString("\\" + string(r))
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r < ' ' || r == 0x7f:
+			fmt.Fprintf(&b, "\\u{%X}", r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	b.WriteByte('\'')
+
+	return b.String()
+}
+
+func (p *printer) value(value *ast.Value) {
+	switch {
+	case value.Number != nil:
+		p.print(value.Number.Raw)
+	case value.String != nil && value.String.Raw != "":
+		p.print(value.String.Raw)
+	case value.String != nil:
+		p.print(quote(value.String.Value))
+	case value.Ident != nil:
+		p.print(*value.Ident)
+	case value.Sub != nil:
+		p.print("(")
+		p.expression(value.Sub)
+		p.print(")")
+	case value.Boolean != nil && *value.Boolean:
+		p.print("true")
+	case value.Boolean != nil:
+		p.print("false")
+	}
+}
@@ -0,0 +1,371 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Package printer implements printing of AST nodes back into canonical
+// Polylang source code.
+package printer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/durudex/go-polylang"
+	"github.com/durudex/go-polylang/ast"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+const DefaultIndent = "    "
+
+// Config controls the output of Fprint.
+type Config struct {
+	// Indent is repeated once per nesting level, DefaultIndent if empty.
+	Indent string
+}
+
+// CommentedProgram bundles a program with the tokens of its source as produced
+// by polylang.CommentLexer, so that Fprint can keep the comments which the
+// parser drops. Comments are placed before the closest following item or
+// statement, or at the end of the line they trailed.
+type CommentedProgram struct {
+	Program *ast.Program
+	Tokens  []lexer.Token
+}
+
+// Fprint prints the node with the default configuration.
+func Fprint(w io.Writer, node any) error {
+	return (&Config{}).Fprint(w, node)
+}
+
+// Fprint prints the node to w. The node must be a pointer to one of the AST
+// types: a program, node, collection, item, field, index, decorator, function,
+// type, statement or expression, or a CommentedProgram. The doc and line
+// comments attached to the nodes are printed unless the node is a
+// CommentedProgram, whose comments are all taken from its tokens. The other
+// comments of a program are placed as in a CommentedProgram, by the positions
+// of its nodes.
+func (c *Config) Fprint(w io.Writer, node any) error {
+	p := &printer{indent: c.Indent}
+	if p.indent == "" {
+		p.indent = DefaultIndent
+	}
+
+	switch n := node.(type) {
+	case *CommentedProgram:
+		comment := polylang.CommentLexer.Symbols()["Comment"]
+
+		for _, token := range n.Tokens {
+			if token.Type == comment {
+				p.comments = append(p.comments, token)
+			} else {
+				p.tokens = append(p.tokens, token)
+			}
+		}
+
+		p.source = true
+		node = n.Program
+	case *ast.Program:
+		p.unattached(n)
+	}
+
+	if err := p.node(node); err != nil {
+		return err
+	}
+
+	_, err := w.Write(p.buf.Bytes())
+
+	return err
+}
+
+type printer struct {
+	buf    bytes.Buffer
+	indent string
+	depth  int
+
+	// comments are the comments that are not printed yet, and tokens are the
+	// remaining source tokens used to find where a node ends. The source is
+	// set when all comments are taken from the tokens.
+	comments []lexer.Token
+	tokens   []lexer.Token
+	source   bool
+
+	// prev is the last comment printed.
+	prev lexer.Token
+
+	// files ranks the files of a program parsed from several files, whose
+	// positions are ordered by file first.
+	files map[string]int
+}
+
+// TypeString returns the type as it is written in Polylang code, on a single
+// line, as used in messages.
+func TypeString(t *ast.Type) string {
+	var buf strings.Builder
+	Fprint(&buf, t) //nolint:errcheck
+
+	return strings.Join(strings.Fields(buf.String()), " ")
+}
+
+func (p *printer) node(node any) error {
+	switch n := node.(type) {
+	case *ast.Program:
+		p.program(n)
+	case *ast.Node:
+		p.topLevel(n)
+	case *ast.Collection:
+		p.collection(n)
+	case *ast.Item:
+		p.item(n)
+	case *ast.Field:
+		p.field(n)
+	case *ast.Index:
+		p.index(n)
+	case *ast.IndexField:
+		p.indexField(n)
+	case *ast.Decorator:
+		p.decorator(n)
+	case *ast.Function:
+		p.function(n)
+	case *ast.Type:
+		p.typ(n)
+	case *ast.Statement:
+		p.statement(n)
+	case *ast.Expression:
+		p.expression(n)
+	case *ast.Value:
+		p.value(n)
+	default:
+		return fmt.Errorf("printer: unsupported node type %T", node)
+	}
+
+	return nil
+}
+
+func (p *printer) print(args ...string) {
+	for _, arg := range args {
+		p.buf.WriteString(arg)
+	}
+}
+
+// newline ends the current line and indents the next one.
+func (p *printer) newline() {
+	p.buf.WriteByte('\n')
+	p.buf.WriteString(strings.Repeat(p.indent, p.depth))
+}
+
+// blank ends the current line and leaves an empty one without trailing
+// indentation.
+func (p *printer) blank() {
+	p.buf.WriteByte('\n')
+	p.newline()
+}
+
+// unattached takes the comments of a program that are not attached to a node
+// to be printed by position. As the source tokens are not known, the
+// positions of the nodes stand in for them.
+func (p *printer) unattached(prog *ast.Program) {
+	attached := map[*ast.CommentGroup]bool{}
+	p.files = map[string]int{}
+
+	ast.Inspect(prog, func(node any) bool {
+		switch n := node.(type) {
+		case nil:
+			return false
+		case *ast.CommentGroup:
+			attached[n] = true
+
+			return false
+		}
+
+		v := reflect.Indirect(reflect.ValueOf(node))
+
+		for _, name := range []string{"Pos", "EndPos"} {
+			field := v.FieldByName(name)
+			if !field.IsValid() {
+				continue
+			}
+
+			pos, ok := field.Interface().(lexer.Position)
+			if !ok || pos.Line == 0 {
+				continue
+			}
+
+			p.rank(pos)
+			p.tokens = append(p.tokens, lexer.Token{Pos: pos})
+		}
+
+		return true
+	})
+
+	for _, group := range prog.Comments {
+		if attached[group] {
+			continue
+		}
+
+		for _, comment := range group.List {
+			p.rank(comment.Pos)
+			p.comments = append(p.comments, lexer.Token{Value: comment.Text, Pos: comment.Pos})
+		}
+	}
+
+	sort.SliceStable(p.tokens, func(i, j int) bool {
+		return p.before(p.tokens[i].Pos, p.tokens[j].Pos)
+	})
+}
+
+// rank gives the file of the position the next rank if it has none yet.
+func (p *printer) rank(pos lexer.Position) {
+	if _, ok := p.files[pos.Filename]; !ok {
+		p.files[pos.Filename] = len(p.files)
+	}
+}
+
+// before reports whether the position a comes before b.
+func (p *printer) before(a, b lexer.Position) bool {
+	if a.Filename != b.Filename {
+		return p.files[a.Filename] < p.files[b.Filename]
+	}
+
+	return a.Offset < b.Offset
+}
+
+// last returns the last source token before the end position of a node.
+func (p *printer) last(end lexer.Position) lexer.Token {
+	i := sort.Search(len(p.tokens), func(i int) bool {
+		return !p.before(p.tokens[i].Pos, end)
+	})
+	if i == 0 {
+		return lexer.Token{}
+	}
+
+	return p.tokens[i-1]
+}
+
+// leading prints the comments preceding pos, each on its own line. An empty
+// line that sets a comment apart in the source is kept.
+func (p *printer) leading(pos lexer.Position) {
+	var printed bool
+
+	for len(p.comments) != 0 && p.before(p.comments[0].Pos, pos) {
+		comment := p.comments[0]
+
+		if p.spaced(comment) {
+			p.gap()
+		}
+
+		p.print(comment.Value)
+		p.newline()
+
+		p.comments = p.comments[1:]
+		p.prev = comment
+		printed = true
+	}
+
+	if printed && p.prev.Pos.Filename == pos.Filename && pos.Line > endLine(p.prev)+1 {
+		p.gap()
+	}
+}
+
+// trailing prints the comments placed on the last line of a node after it.
+func (p *printer) trailing(end lexer.Position) {
+	if len(p.comments) == 0 {
+		return
+	}
+
+	last := p.last(end).Pos
+
+	for len(p.comments) != 0 && p.comments[0].Pos.Filename == last.Filename &&
+		p.comments[0].Pos.Line == last.Line && p.before(p.comments[0].Pos, end) {
+		p.print(" ", p.comments[0].Value)
+
+		p.prev = p.comments[0]
+		p.comments = p.comments[1:]
+	}
+}
+
+// closing prints the comments left inside a block that ends at end, each on
+// its own line, and reports whether there were any.
+func (p *printer) closing(end lexer.Position) bool {
+	if len(p.comments) == 0 {
+		return false
+	}
+
+	brace := p.last(end).Pos
+
+	var found bool
+
+	for len(p.comments) != 0 && p.before(p.comments[0].Pos, brace) {
+		comment := p.comments[0]
+
+		if p.spaced(comment) {
+			p.blank()
+		} else {
+			p.newline()
+		}
+
+		p.print(comment.Value)
+
+		p.comments = p.comments[1:]
+		p.prev = comment
+		found = true
+	}
+
+	return found
+}
+
+// rest prints the comments that follow everything else.
+func (p *printer) rest() {
+	for _, comment := range p.comments {
+		if p.buf.Len() != 0 && p.spaced(comment) {
+			p.print("\n")
+		}
+
+		p.print(comment.Value, "\n")
+		p.prev = comment
+	}
+
+	p.comments = nil
+}
+
+// spaced reports whether an empty line is put between the comment and the
+// code or comment before it in the source.
+func (p *printer) spaced(comment lexer.Token) bool {
+	var line int
+
+	if last := p.last(comment.Pos).Pos; last.Filename == comment.Pos.Filename {
+		line = last.Line
+	}
+
+	if p.prev.Pos.Filename == comment.Pos.Filename && endLine(p.prev) > line {
+		line = endLine(p.prev)
+	}
+
+	return line != 0 && comment.Pos.Line > line+1
+}
+
+// gap turns the current line into an empty one followed by a new line, unless
+// it is the first line or already follows an empty line.
+func (p *printer) gap() {
+	indent := strings.Repeat(p.indent, p.depth)
+	b := p.buf.Bytes()
+
+	if !bytes.HasSuffix(b, []byte("\n"+indent)) || bytes.HasSuffix(b, []byte("\n\n"+indent)) {
+		return
+	}
+
+	p.buf.Truncate(len(b) - len(indent))
+	p.newline()
+}
+
+// endLine returns the line on which the token ends.
+func endLine(token lexer.Token) int {
+	return token.Pos.Line + strings.Count(token.Value, "\n")
+}
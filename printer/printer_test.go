@@ -0,0 +1,323 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package printer_test
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/durudex/go-polylang/ast"
+	"github.com/durudex/go-polylang/parser"
+	"github.com/durudex/go-polylang/printer"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+var positionType = reflect.TypeOf(lexer.Position{})
+
+// zeroPos resets every source position reachable from v, so that trees
+// parsed from different sources can be compared.
+func zeroPos(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			zeroPos(v.Elem())
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			zeroPos(v.Index(i))
+		}
+	case reflect.Struct:
+		if v.Type() == positionType {
+			v.Set(reflect.Zero(positionType))
+
+			return
+		}
+
+		for i := 0; i < v.NumField(); i++ {
+			zeroPos(v.Field(i))
+		}
+	}
+}
+
+func TestFprint_Fixture(t *testing.T) {
+	want, err := os.ReadFile("fixtures/account.polylang")
+	if err != nil {
+		t.Fatal("error: reading fixtures file: ", err)
+	}
+
+	prog, err := parser.Must.ParseBytes("", want)
+	if err != nil {
+		t.Fatal("error: parsing polylang code: ", err)
+	}
+
+	var got bytes.Buffer
+	if err := printer.Fprint(&got, prog); err != nil {
+		t.Fatal("error: printing program: ", err)
+	}
+
+	if got.String() != string(want) {
+		t.Fatalf("error: printed program does not match:\n%s", got.String())
+	}
+}
+
+var FprintTests = map[string]struct {
+	code string
+	want string
+}{
+	"Collection": {
+		code: "@public collection Test{id:string;name?:string;function get():string{return this.name;}}",
+		want: "@public\ncollection Test {\n    id: string;\n    name?: string;\n\n" +
+			"    function get(): string {\n        return this.name;\n    }\n}\n",
+	},
+	"Empty Collection": {
+		code: "collection Empty {}",
+		want: "collection Empty {}\n",
+	},
+	"Short Function": {
+		code: "collection Test { set(v: number) { this.v = v; } }",
+		want: "collection Test {\n    function set(v: number) {\n        this.v = v;\n    }\n}\n",
+	},
+	"Index": {
+		code: "collection Test { @index([a, asc], [b, desc], c.d); }",
+		want: "collection Test {\n    @index(a, [b, desc], c.d);\n}\n",
+	},
+	"Expressions": {
+		code: "function f() { return ( a+b )*-c[0].d(e,f) ** 2; }",
+		want: "function f() {\n    return (a + b) * -c[0].d(e, f) ** 2;\n}\n",
+	},
+	"If Else": {
+		code: "function f() { if (a) {} else { if (b) return 1; else { break; } } }",
+		want: "function f() {\n    if (a) {} else {\n        if (b) return 1; else {\n" +
+			"            break;\n        }\n    }\n}\n",
+	},
+	"Literals": {
+		code: "function f() { return true != false && 1_000 + 0x1F + .5e1 + 'it\\'s' + \"\\u{1F600}\"; }",
+		want: "function f() {\n    return true != false && 1_000 + 0x1F + .5e1 + 'it\\'s' + \"\\u{1F600}\";\n}\n",
+	},
+	"Array and Object": {
+		code: "function f() { this.tags = [ ]; this.info = {name:n,'full name':[a,{}]}; }",
+		want: "function f() {\n    this.tags = [];\n    this.info = { name: n, 'full name': [a, {}] };\n}\n",
+	},
+	"Update": {
+		code: "function f() { for (let i = 0; i < n; i++) { - --a[i]; +b++; } }",
+		want: "function f() {\n    for (let i = 0; i < n; i++) {\n        - --a[i];\n        +b++;\n    }\n}\n",
+	},
+	"Unary": {
+		code: "function f() { return !!a && ~~b == - -c + + +d + -+e; }",
+		want: "function f() {\n    return !!a && ~~b == - -c + + +d + -+e;\n}\n",
+	},
+	"Statements": {
+		code: "function f() { if (a) return; else if (b) { continue; } else while (c) {} { let d = 1; } }",
+		want: "function f() {\n    if (a) return; else if (b) {\n        continue;\n    } else while (c) {}\n" +
+			"    {\n        let d = 1;\n    }\n}\n",
+	},
+	"Multiple Nodes": {
+		code: "collection A {} collection B {}",
+		want: "collection A {}\n\ncollection B {}\n",
+	},
+}
+
+func TestFprint(t *testing.T) {
+	for name, test := range FprintTests {
+		t.Run(name, func(t *testing.T) {
+			prog, err := parser.Must.ParseString("", test.code)
+			if err != nil {
+				t.Fatal("error: parsing polylang code: ", err)
+			}
+
+			var got bytes.Buffer
+			if err := printer.Fprint(&got, prog); err != nil {
+				t.Fatal("error: printing program: ", err)
+			}
+
+			if got.String() != test.want {
+				t.Fatalf("error: printed program does not match:\n%s", got.String())
+			}
+		})
+	}
+}
+
+func TestFprint_RoundTrip(t *testing.T) {
+	for name, test := range FprintTests {
+		t.Run(name, func(t *testing.T) {
+			want, err := parser.Must.ParseString("", test.code)
+			if err != nil {
+				t.Fatal("error: parsing polylang code: ", err)
+			}
+
+			var buf bytes.Buffer
+			if err := printer.Fprint(&buf, want); err != nil {
+				t.Fatal("error: printing program: ", err)
+			}
+
+			got, err := parser.Must.ParseString("", buf.String())
+			if err != nil {
+				t.Fatal("error: parsing printed code: ", err)
+			}
+
+			zeroPos(reflect.ValueOf(want))
+			zeroPos(reflect.ValueOf(got))
+
+			if !reflect.DeepEqual(got, want) {
+				t.Fatal("error: program does not match after round trip")
+			}
+		})
+	}
+}
+
+var ExpressionTests = map[string]struct {
+	expr *ast.Expression
+	want string
+}{
+	"Left Operand": {
+		expr: binary(binary(ident("a"), ast.Add, ident("b")), ast.Multiply, ident("c")),
+		want: "(a + b) * c",
+	},
+	"Right Operand": {
+		expr: binary(ident("a"), ast.Subtract, binary(ident("b"), ast.Subtract, ident("c"))),
+		want: "a - (b - c)",
+	},
+	"Right Associative": {
+		expr: binary(ident("a"), ast.Exponent, binary(ident("b"), ast.Exponent, ident("c"))),
+		want: "a ** b ** c",
+	},
+	"Unary": {
+		expr: &ast.Expression{Unary: &ast.UnaryExpr{
+			Operator: ast.Not,
+			Operand:  binary(ident("a"), ast.And, ident("b")),
+		}},
+		want: "!(a && b)",
+	},
+	"Object": {
+		expr: &ast.Expression{Object: &ast.ObjectLit{Properties: []*ast.Property{
+			{Key: "name", Value: ident("a")},
+			{Key: "it's\n", Value: ident("b")},
+			{Key: "1a", Value: ident("c")},
+		}}},
+		want: "{ name: a, 'it\\'s\\n': b, '1a': c }",
+	},
+	"String": {
+		expr: &ast.Expression{Value: &ast.Value{String: &ast.StringLit{Value: "it's\n"}}},
+		want: "'it\\'s\\n'",
+	},
+	"Member": {
+		expr: &ast.Expression{Member: &ast.MemberExpr{
+			Object:   binary(ident("a"), ast.Or, ident("b")),
+			Property: "c",
+		}},
+		want: "(a || b).c",
+	},
+}
+
+func ident(v string) *ast.Expression {
+	return &ast.Expression{Value: &ast.Value{Ident: &v}}
+}
+
+func binary(left *ast.Expression, op ast.Operator, right *ast.Expression) *ast.Expression {
+	return &ast.Expression{
+		Binary: &ast.BinaryExpr{Left: left, Operator: op, Right: right},
+	}
+}
+
+func TestFprint_Expression(t *testing.T) {
+	for name, test := range ExpressionTests {
+		t.Run(name, func(t *testing.T) {
+			var got bytes.Buffer
+			if err := printer.Fprint(&got, test.expr); err != nil {
+				t.Fatal("error: printing expression: ", err)
+			}
+
+			if got.String() != test.want {
+				t.Fatal("error: printed expression does not match: ", got.String())
+			}
+		})
+	}
+}
+
+func TestTypeString(t *testing.T) {
+	prog, err := parser.Must.ParseString("", "collection A { a: map<string, { b?: number[]; c: T; }>; }")
+	if err != nil {
+		t.Fatal("error: parsing polylang code: ", err)
+	}
+
+	got := printer.TypeString(&prog.Nodes[0].Collection.Items[0].Field.Type)
+	if want := "map<string, { b?: number[]; c: T; }>"; got != want {
+		t.Fatal("error: type does not match: ", got)
+	}
+}
+
+func TestConfig_Fprint(t *testing.T) {
+	prog, err := parser.Must.ParseString("", "function f() { if (a) { b(); } }")
+	if err != nil {
+		t.Fatal("error: parsing polylang code: ", err)
+	}
+
+	want := "function f() {\n\tif (a) {\n\t\tb();\n\t}\n}\n"
+
+	var got bytes.Buffer
+	if err := (&printer.Config{Indent: "\t"}).Fprint(&got, prog); err != nil {
+		t.Fatal("error: printing program: ", err)
+	}
+
+	if got.String() != want {
+		t.Fatal("error: printed program does not match: ", got.String())
+	}
+}
+
+func TestFprint_AttachedComments(t *testing.T) {
+	code := "// A doc.\ncollection A {\n// id doc.\nid: string; // id line.\ninfo: { name: string; // name line.\n};\n" +
+		"// index doc.\n@index(id);\n// f doc.\n@read function f() { // empty\n}\n}\n// g doc.\nfunction g() {}"
+
+	prog, err := parser.ParseFile("", []byte(code), parser.ParseComments)
+	if err != nil {
+		t.Fatal("error: parsing polylang code: ", err)
+	}
+
+	want := "// A doc.\ncollection A {\n    // id doc.\n    id: string; // id line.\n    info: {\n        name: string; // name line.\n    };\n\n" +
+		"    // index doc.\n    @index(id);\n\n    // f doc.\n    @read\n    function f() {} // empty\n}\n\n// g doc.\nfunction g() {}\n"
+
+	var got bytes.Buffer
+	if err := printer.Fprint(&got, prog); err != nil {
+		t.Fatal("error: printing program: ", err)
+	}
+
+	if got.String() != want {
+		t.Fatalf("error: printed program does not match:\n%s", got.String())
+	}
+}
+
+func TestFprint_UnattachedComments(t *testing.T) {
+	code := "// header\n\ncollection A {\n// before id\n\nid: string;\n\nfunction f() {\nlet a = 1; // after a\n" +
+		"// between\n\nlet b = 2;\n// dangling\n}\n// end of A\n} // closing\n\n// footer\n"
+
+	prog, err := parser.ParseFile("", []byte(code), parser.ParseComments)
+	if err != nil {
+		t.Fatal("error: parsing polylang code: ", err)
+	}
+
+	want := "// header\n\ncollection A {\n    // before id\n\n    id: string;\n\n    function f() {\n        let a = 1; // after a\n" +
+		"        // between\n\n        let b = 2;\n        // dangling\n    }\n    // end of A\n} // closing\n\n// footer\n"
+
+	var got bytes.Buffer
+	if err := printer.Fprint(&got, prog); err != nil {
+		t.Fatal("error: printing program: ", err)
+	}
+
+	if got.String() != want {
+		t.Fatalf("error: printed program does not match:\n%s", got.String())
+	}
+}
+
+func TestFprint_Unsupported(t *testing.T) {
+	if err := printer.Fprint(&bytes.Buffer{}, "program"); err == nil {
+		t.Fatal("error: expected unsupported node error")
+	}
+}
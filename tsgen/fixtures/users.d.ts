@@ -0,0 +1,45 @@
+// Code generated by polylang-gen-ts. DO NOT EDIT.
+
+export interface User {
+    id: string;
+    publicKey: PublicKey;
+    name?: string;
+    age?: number;
+    active: boolean;
+    avatar?: Uint8Array;
+    tags: Array<string>;
+    scores?: Record<number, number>;
+    profile: {
+        website_url?: string;
+        social: Record<string, {
+            handle: string;
+        }>;
+    };
+    account: Reference<"Account">;
+    owner?: Reference;
+}
+
+export interface UserMethods {
+    setName(name: string): void;
+    follow(account: Reference<"Account">, note?: string): boolean;
+}
+
+export interface Account {
+    id: string;
+}
+
+/** A reference to a record of the collection T. */
+export interface Reference<T extends string = string> {
+    collectionId: `${string}/${T}`;
+    id: string;
+}
+
+/** A public key in the JSON Web Key format. */
+export interface PublicKey {
+    kty: "EC";
+    crv: "secp256k1";
+    alg: "ES256K";
+    use: "sig";
+    x: string;
+    y: string;
+}
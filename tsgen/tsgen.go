@@ -0,0 +1,405 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Package tsgen generates TypeScript type definitions for Polylang collections.
+package tsgen
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/durudex/go-polylang/compiler"
+	"github.com/durudex/go-polylang/metadata"
+)
+
+const DefaultIndent = "    "
+
+// Config controls the output of Fprint.
+type Config struct {
+	// Indent is repeated once per nesting level, DefaultIndent if empty.
+	Indent string
+}
+
+// Fprint generates the definitions with the default configuration.
+func Fprint(w io.Writer, node any) error {
+	return (&Config{}).Fprint(w, node)
+}
+
+// Fprint generates a .d.ts file for the collections of the node, which is any
+// node accepted by compiler.Collections, and writes it to w.
+//
+// Every collection gets an interface for its records and, if it has methods,
+// an interface named after it with a Methods suffix for their signatures.
+// Public keys are JSON Web Keys, bytes are Uint8Array values and records are
+// references whose collectionId ends with the name of the collection.
+// Declarations whose names collide with a collection, and collections whose
+// names collide with the global Array, Record and Uint8Array types, are
+// renamed with a trailing underscore. Optional parameters followed by a
+// required one may be undefined instead.
+func (c *Config) Fprint(w io.Writer, node any) error {
+	colls, err := compiler.Collections(node)
+	if err != nil {
+		return err
+	}
+
+	g := &generator{indent: c.Indent, names: make(map[string]bool)}
+	if g.indent == "" {
+		g.indent = DefaultIndent
+	}
+
+	// The globals are named first and the collections next, so that the
+	// other declarations are renamed when their names collide.
+	for _, name := range globals {
+		g.names[name] = true
+	}
+
+	interfaces := make([]string, len(colls))
+	for i, coll := range colls {
+		interfaces[i] = g.unique(coll.Name)
+	}
+
+	g.referenceName, g.publicKeyName = g.unique("Reference"), g.unique("PublicKey")
+
+	g.print("// Code generated by polylang-gen-ts. DO NOT EDIT.\n")
+
+	for i, coll := range colls {
+		if err := g.collection(coll, interfaces[i]); err != nil {
+			return fmt.Errorf("collection %s: %w", coll.Name, err)
+		}
+	}
+
+	if g.reference {
+		g.print("\n/** A reference to a record of the collection T. */\n",
+			"export interface ", g.referenceName, "<T extends string = string> {\n",
+			g.indent, "collectionId: `${string}/${T}`;\n",
+			g.indent, "id: string;\n",
+			"}\n")
+	}
+
+	if g.publicKey {
+		g.print("\n/** A public key in the JSON Web Key format. */\n",
+			"export interface ", g.publicKeyName, " {\n",
+			g.indent, "kty: \"EC\";\n",
+			g.indent, "crv: \"secp256k1\";\n",
+			g.indent, "alg: \"ES256K\";\n",
+			g.indent, "use: \"sig\";\n",
+			g.indent, "x: string;\n",
+			g.indent, "y: string;\n",
+			"}\n")
+	}
+
+	_, err = w.Write(g.buf.Bytes())
+
+	return err
+}
+
+// globals are the global types used by the definitions, which must not be
+// shadowed by a declaration.
+var globals = []string{"Array", "Record", "Uint8Array"}
+
+type generator struct {
+	buf    bytes.Buffer
+	indent string
+	depth  int
+
+	// names are the names of the declared interfaces, and referenceName and
+	// publicKeyName are the names of the shared declarations, which are
+	// printed if reference and publicKey report that they are used.
+	names         map[string]bool
+	referenceName string
+	publicKeyName string
+	reference     bool
+	publicKey     bool
+}
+
+// unique returns the name, followed by underscores if it is already declared,
+// and declares it.
+func (g *generator) unique(name string) string {
+	for g.names[name] {
+		name += "_"
+	}
+
+	g.names[name] = true
+
+	return name
+}
+
+func (g *generator) print(args ...string) {
+	for _, arg := range args {
+		g.buf.WriteString(arg)
+	}
+}
+
+func (g *generator) newline() {
+	g.print("\n", strings.Repeat(g.indent, g.depth))
+}
+
+// collection declares the interfaces of a collection, where name is the name
+// of the interface for its records.
+func (g *generator) collection(coll *metadata.Collection, name string) error {
+	var (
+		fields  []metadata.ObjectField
+		methods []*metadata.Method
+	)
+
+	for _, attr := range coll.Attributes {
+		prop, ok, err := attr.Property()
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			fields = append(fields, metadata.ObjectField{
+				Name: prop.Name, Type: prop.Type, Required: prop.Required,
+			})
+
+			continue
+		}
+
+		method, ok, err := attr.Method()
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			methods = append(methods, method)
+		}
+	}
+
+	g.print("\nexport interface ", name, " ")
+
+	if err := g.object(fields); err != nil {
+		return err
+	}
+
+	g.print("\n")
+
+	if len(methods) == 0 {
+		return nil
+	}
+
+	g.print("\nexport interface ", g.unique(coll.Name+"Methods"), " {")
+	g.depth++
+
+	for _, method := range methods {
+		g.newline()
+
+		if err := g.method(method); err != nil {
+			return fmt.Errorf("method %s: %w", method.Name, err)
+		}
+	}
+
+	g.depth--
+	g.print("\n}\n")
+
+	return nil
+}
+
+func (g *generator) method(method *metadata.Method) error {
+	g.print(property(method.Name), "(")
+
+	var params []*metadata.Parameter
+
+	for _, attr := range method.Attributes {
+		param, ok, err := attr.Parameter()
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			params = append(params, param)
+		}
+	}
+
+	// TypeScript does not allow an optional parameter before a required one,
+	// so such a parameter is required but may be undefined.
+	required := 0
+
+	for i, param := range params {
+		if param.Required {
+			required = i + 1
+		}
+	}
+
+	for i, param := range params {
+		if i != 0 {
+			g.print(", ")
+		}
+
+		g.print(param.Name)
+
+		if !param.Required && i >= required {
+			g.print("?")
+		}
+
+		g.print(": ")
+
+		if err := g.typ(param.Type); err != nil {
+			return fmt.Errorf("parameter %s: %w", param.Name, err)
+		}
+
+		if !param.Required && i < required {
+			g.print(" | undefined")
+		}
+	}
+
+	returns := "void"
+
+	for _, attr := range method.Attributes {
+		if value, ok, err := attr.ReturnValue(); err != nil {
+			return err
+		} else if ok {
+			var buf bytes.Buffer
+
+			buf, g.buf = g.buf, buf
+			err := g.typ(value.Type)
+			buf, g.buf = g.buf, buf
+
+			if err != nil {
+				return fmt.Errorf("return value: %w", err)
+			}
+
+			returns = buf.String()
+		}
+	}
+
+	g.print("): ", returns, ";")
+
+	return nil
+}
+
+// object prints an object type with a member for every field.
+func (g *generator) object(fields []metadata.ObjectField) error {
+	if len(fields) == 0 {
+		g.print("{}")
+
+		return nil
+	}
+
+	g.print("{")
+	g.depth++
+
+	for _, field := range fields {
+		g.newline()
+		g.print(property(field.Name))
+
+		if !field.Required {
+			g.print("?")
+		}
+
+		g.print(": ")
+
+		if err := g.typ(field.Type); err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+
+		g.print(";")
+	}
+
+	g.depth--
+	g.newline()
+	g.print("}")
+
+	return nil
+}
+
+func (g *generator) typ(t metadata.Type) error {
+	switch t.Kind {
+	case "primitive":
+		primitive, _, err := t.Primitive()
+		if err != nil {
+			return err
+		}
+
+		switch primitive.Value {
+		case metadata.PrimitiveTypeString, metadata.PrimitiveTypeNumber, metadata.PrimitiveTypeBoolean:
+			g.print(string(primitive.Value))
+		case metadata.PrimitiveTypeBytes:
+			g.print("Uint8Array")
+		default:
+			return fmt.Errorf("unknown primitive type %q", primitive.Value)
+		}
+	case "publickey":
+		g.publicKey = true
+		g.print(g.publicKeyName)
+	case "record":
+		g.reference = true
+		g.print(g.referenceName)
+	case "foreignrecord":
+		rec, _, err := t.ForeignRecord()
+		if err != nil {
+			return err
+		}
+
+		g.reference = true
+		g.print(g.referenceName, "<", strconv.Quote(rec.Collection), ">")
+	case "array":
+		array, _, err := t.Array()
+		if err != nil {
+			return err
+		}
+
+		g.print("Array<")
+
+		if err := g.typ(array.Value); err != nil {
+			return err
+		}
+
+		g.print(">")
+	case "map":
+		mp, _, err := t.Map()
+		if err != nil {
+			return err
+		}
+
+		key, ok, err := mp.Key.Primitive()
+		if err != nil {
+			return err
+		}
+
+		if !ok || key.Value != metadata.PrimitiveTypeString && key.Value != metadata.PrimitiveTypeNumber {
+			return fmt.Errorf("invalid map key type %s", mp.Key.Kind)
+		}
+
+		g.print("Record<", string(key.Value), ", ")
+
+		if err := g.typ(mp.Value); err != nil {
+			return err
+		}
+
+		g.print(">")
+	case "object":
+		obj, _, err := t.Object()
+		if err != nil {
+			return err
+		}
+
+		return g.object(obj.Fields)
+	default:
+		return fmt.Errorf("unknown type kind %q", t.Kind)
+	}
+
+	return nil
+}
+
+// property returns a property name, quoted if it is not an identifier.
+func property(name string) string {
+	for i, r := range name {
+		if !(r == '_' || r == '$' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || i != 0 && '0' <= r && r <= '9') {
+			return strconv.Quote(name)
+		}
+	}
+
+	if name == "" {
+		return `""`
+	}
+
+	return name
+}
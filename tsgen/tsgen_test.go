@@ -0,0 +1,163 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package tsgen_test
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/durudex/go-polylang/compiler"
+	"github.com/durudex/go-polylang/metadata"
+	"github.com/durudex/go-polylang/parser"
+	"github.com/durudex/go-polylang/tsgen"
+)
+
+func TestFprint(t *testing.T) {
+	prog, err := parser.Parse("fixtures/users.polylang")
+	if err != nil {
+		t.Fatal("error: parsing polylang code: ", err)
+	}
+
+	root, err := compiler.Compile(prog, "ns")
+	if err != nil {
+		t.Fatal("error: compiling program: ", err)
+	}
+
+	want, err := os.ReadFile("fixtures/users.d.ts")
+	if err != nil {
+		t.Fatal("error: reading fixtures file: ", err)
+	}
+
+	for name, node := range map[string]any{"Program": prog, "Metadata": root} {
+		t.Run(name, func(t *testing.T) {
+			var got bytes.Buffer
+			if err := tsgen.Fprint(&got, node); err != nil {
+				t.Fatal("error: generating definitions: ", err)
+			}
+
+			if got.String() != string(want) {
+				t.Fatalf("error: definitions do not match:\n%s", got.String())
+			}
+		})
+	}
+}
+
+var FprintTests = map[string]struct {
+	code string
+	want string
+}{
+	"Empty": {
+		code: "collection T {}",
+		want: "// Code generated by polylang-gen-ts. DO NOT EDIT.\n\n" +
+			"export interface T {}\n",
+	},
+	"Indent": {
+		code: "collection T { a: { b: number; }; function f(): string { return ''; } }",
+		want: "// Code generated by polylang-gen-ts. DO NOT EDIT.\n\n" +
+			"export interface T {\n" +
+			"\ta: {\n" +
+			"\t\tb: number;\n" +
+			"\t};\n" +
+			"}\n\n" +
+			"export interface TMethods {\n" +
+			"\tf(): string;\n" +
+			"}\n",
+	},
+	"Parameters": {
+		code: "collection T { function f(a?: string, b: number, c?: boolean) {} }",
+		want: "// Code generated by polylang-gen-ts. DO NOT EDIT.\n\n" +
+			"export interface T {}\n\n" +
+			"export interface TMethods {\n" +
+			"\tf(a: string | undefined, b: number, c?: boolean): void;\n" +
+			"}\n",
+	},
+	"Collisions": {
+		code: "collection Reference { owner: PublicKey; } collection PublicKey { r: record; } " +
+			"collection T { function f() {} } collection TMethods {}",
+		want: "// Code generated by polylang-gen-ts. DO NOT EDIT.\n\n" +
+			"export interface Reference {\n" +
+			"\towner: PublicKey_;\n" +
+			"}\n\n" +
+			"export interface PublicKey {\n" +
+			"\tr: Reference_;\n" +
+			"}\n\n" +
+			"export interface T {}\n\n" +
+			"export interface TMethods_ {\n" +
+			"\tf(): void;\n" +
+			"}\n\n" +
+			"export interface TMethods {}\n\n" +
+			"/** A reference to a record of the collection T. */\n" +
+			"export interface Reference_<T extends string = string> {\n" +
+			"\tcollectionId: `${string}/${T}`;\n" +
+			"\tid: string;\n" +
+			"}\n\n" +
+			"/** A public key in the JSON Web Key format. */\n" +
+			"export interface PublicKey_ {\n" +
+			"\tkty: \"EC\";\n" +
+			"\tcrv: \"secp256k1\";\n" +
+			"\talg: \"ES256K\";\n" +
+			"\tuse: \"sig\";\n" +
+			"\tx: string;\n" +
+			"\ty: string;\n" +
+			"}\n",
+	},
+	"Globals": {
+		code: "collection Record { a: map<string, Array>; } collection Array { b: bytes[]; function f() {} } collection Uint8Array {}",
+		want: "// Code generated by polylang-gen-ts. DO NOT EDIT.\n\n" +
+			"export interface Record_ {\n" +
+			"\ta: Record<string, Reference<\"Array\">>;\n" +
+			"}\n\n" +
+			"export interface Array_ {\n" +
+			"\tb: Array<Uint8Array>;\n" +
+			"}\n\n" +
+			"export interface ArrayMethods {\n" +
+			"\tf(): void;\n" +
+			"}\n\n" +
+			"export interface Uint8Array_ {}\n\n" +
+			"/** A reference to a record of the collection T. */\n" +
+			"export interface Reference<T extends string = string> {\n" +
+			"\tcollectionId: `${string}/${T}`;\n" +
+			"\tid: string;\n" +
+			"}\n",
+	},
+}
+
+func TestConfig_Fprint(t *testing.T) {
+	for name, test := range FprintTests {
+		t.Run(name, func(t *testing.T) {
+			prog, err := parser.Must.ParseString("", test.code)
+			if err != nil {
+				t.Fatal("error: parsing polylang code: ", err)
+			}
+
+			var got bytes.Buffer
+			if err := (&tsgen.Config{Indent: "\t"}).Fprint(&got, prog); err != nil {
+				t.Fatal("error: generating definitions: ", err)
+			}
+
+			if got.String() != test.want {
+				t.Fatalf("error: definitions do not match:\n%s", got.String())
+			}
+		})
+	}
+}
+
+func TestFprint_Error(t *testing.T) {
+	data := `[{"kind":"collection","namespace":{"kind":"namespace","value":"ns"},"name":"T","attributes":[` +
+		`{"kind":"property","name":"a","type":{"kind":"map","key":{"kind":"record"},"value":{"kind":"primitive","value":"string"}},"directives":[],"required":true}]}]`
+
+	root, err := metadata.Parse([]byte(data))
+	if err != nil {
+		t.Fatal("error: parsing metadata: ", err)
+	}
+
+	if err := tsgen.Fprint(&bytes.Buffer{}, root); err == nil {
+		t.Fatal("error: expected an error for an invalid map key")
+	}
+}
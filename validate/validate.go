@@ -0,0 +1,127 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+// Package validate reports the values of records that do not fit the
+// properties of their collection.
+package validate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/durudex/go-polylang/metadata"
+)
+
+type Code int
+
+const (
+	TypeMismatch Code = iota + 1
+	MissingProperty
+	UnknownProperty
+	InvalidPublicKey
+	InvalidBytes
+	InvalidReference
+)
+
+var CodeToString = map[Code]string{
+	TypeMismatch:     "type-mismatch",
+	MissingProperty:  "missing-property",
+	UnknownProperty:  "unknown-property",
+	InvalidPublicKey: "invalid-public-key",
+	InvalidBytes:     "invalid-bytes",
+	InvalidReference: "invalid-reference",
+}
+
+func (c Code) String() string { return CodeToString[c] }
+
+// Error is a single problem found in a record. Path is the JSON pointer of the
+// offending value, which is empty for the record itself.
+type Error struct {
+	Path    string
+	Code    Code
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("#%s: %s (%s)", e.Path, e.Message, e.Code)
+}
+
+// Errors is the list of problems found in a record, in the order of the
+// properties of the collection.
+type Errors []*Error
+
+func (e Errors) Error() string {
+	lines := make([]string, len(e))
+
+	for i, err := range e {
+		lines[i] = err.Error()
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Err returns the errors as an error, or nil when there are none.
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+// Record returns the problems found in a record of the collection. Values are
+// those decoded by encoding/json, though any Go number, slice and map with
+// string keys is accepted, and bytes may also be a []byte. A nil value is the
+// same as a missing one. The error is only set if the metadata is malformed.
+func Record(coll *metadata.Collection, record map[string]any) (Errors, error) {
+	var fields []metadata.ObjectField
+
+	for _, attr := range coll.Attributes {
+		prop, ok, err := attr.Property()
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			fields = append(fields, metadata.ObjectField{
+				Name: prop.Name, Type: prop.Type, Required: prop.Required,
+			})
+		}
+	}
+
+	v := &validator{}
+
+	if err := v.object("", fields, record); err != nil {
+		return nil, fmt.Errorf("collection %s: %w", coll.Name, err)
+	}
+
+	return v.errors, nil
+}
+
+// JSON returns the problems found in a record encoded as JSON. The error is
+// set if the data is not JSON or the metadata is malformed.
+func JSON(coll *metadata.Collection, data []byte) (Errors, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var record any
+	if err := dec.Decode(&record); err != nil {
+		return nil, err
+	}
+
+	obj, ok := record.(map[string]any)
+	if !ok {
+		return Errors{{
+			Code:    TypeMismatch,
+			Message: fmt.Sprintf("expected object, got %s", kind(record)),
+		}}, nil
+	}
+
+	return Record(coll, obj)
+}
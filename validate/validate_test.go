@@ -0,0 +1,160 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package validate_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/durudex/go-polylang/compiler"
+	"github.com/durudex/go-polylang/metadata"
+	"github.com/durudex/go-polylang/parser"
+	"github.com/durudex/go-polylang/validate"
+)
+
+const code = `
+collection User {
+    id: string;
+    publicKey?: PublicKey;
+    age?: number;
+    avatar?: bytes;
+    tags?: string[];
+    scores?: map<number, boolean>;
+    profile?: {
+        name: string;
+    };
+    account?: Account;
+    any?: record;
+}
+`
+
+const key = `{"kty":"EC","crv":"secp256k1","alg":"ES256K","use":"sig",` +
+	`"x":"nnzHFO4bZ239bIuAo8t0wQwoH3m4ZAWBFBL6VQLc6Dk","y":"cjM6uYZeV5P4s8C4wWK3BBD0_2rB6ZM8Gmd7yXUuzOw"}`
+
+func collection(t *testing.T) *metadata.Collection {
+	prog, err := parser.Must.ParseString("", code)
+	if err != nil {
+		t.Fatal("error: parsing polylang code: ", err)
+	}
+
+	coll, err := compiler.Collection(prog.Nodes[0].Collection, "ns")
+	if err != nil {
+		t.Fatal("error: compiling collection: ", err)
+	}
+
+	return coll
+}
+
+var JSONTests = map[string]struct {
+	data string
+	want []string
+}{
+	"OK": {
+		data: `{"id":"1","publicKey":` + key + `,"age":42,"avatar":"AQI=","tags":["a"],` +
+			`"scores":{"1":true,"-2.5":false},"profile":{"name":"n"},` +
+			`"account":{"collectionId":"ns/Account","id":"2"},"any":{"collectionId":"ns/User","id":"3"}}`,
+	},
+	"Null": {
+		data: `{"id":"1","age":null}`,
+	},
+	"Type mismatch": {
+		data: `{"id":1,"age":"42","tags":["a",2],"profile":[]}`,
+		want: []string{"/id type-mismatch", "/age type-mismatch", "/tags/1 type-mismatch", "/profile type-mismatch"},
+	},
+	"Missing property": {
+		data: `{"profile":{}}`,
+		want: []string{"/id missing-property", "/profile/name missing-property"},
+	},
+	"Unknown property": {
+		data: `{"id":"1","profile":{"name":"n","a/b":1},"z":1,"a":2}`,
+		want: []string{"/profile/a~1b unknown-property", "/a unknown-property", "/z unknown-property"},
+	},
+	"Public key": {
+		data: `{"id":"1","publicKey":{"kty":"RSA","crv":"secp256k1","alg":"ES256K","use":"sig","x":"AQI","y":1}}`,
+		want: []string{"/publicKey/kty invalid-public-key", "/publicKey/x invalid-public-key", "/publicKey/y invalid-public-key"},
+	},
+	"Bytes": {
+		data: `{"id":"1","avatar":"not base64"}`,
+		want: []string{"/avatar invalid-bytes"},
+	},
+	"Map": {
+		data: `{"id":"1","scores":{"a":true,"1":"b"}}`,
+		want: []string{"/scores/1 type-mismatch", "/scores/a type-mismatch"},
+	},
+	"Reference": {
+		data: `{"id":"1","account":{"collectionId":"ns/User","id":2,"x":1},"any":{}}`,
+		want: []string{
+			"/account/collectionId invalid-reference", "/account/id type-mismatch", "/account/x unknown-property",
+			"/any/collectionId missing-property", "/any/id missing-property",
+		},
+	},
+	"Reference name": {
+		data: `{"id":"1","account":{"collectionId":"Account","id":"2"}}`,
+	},
+	"Reference suffix": {
+		data: `{"id":"1","account":{"collectionId":"nsAccount","id":"2"}}`,
+		want: []string{"/account/collectionId invalid-reference"},
+	},
+	"Not object": {
+		data: `[]`,
+		want: []string{" type-mismatch"},
+	},
+}
+
+func TestJSON(t *testing.T) {
+	coll := collection(t)
+
+	for name, test := range JSONTests {
+		t.Run(name, func(t *testing.T) {
+			errs, err := validate.JSON(coll, []byte(test.data))
+			if err != nil {
+				t.Fatal("error: validating record: ", err)
+			}
+
+			var got []string
+			for _, e := range errs {
+				got = append(got, e.Path+" "+e.Code.String())
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("error: errors do not match:\n%s", errs.Error())
+			}
+		})
+	}
+}
+
+func TestRecord(t *testing.T) {
+	errs, err := validate.Record(collection(t), map[string]any{
+		"id":     "1",
+		"age":    42,
+		"avatar": []byte{1, 2},
+		"tags":   []string{"a"},
+		"scores": map[string]bool{"1": true},
+	})
+	if err != nil {
+		t.Fatal("error: validating record: ", err)
+	}
+
+	if err := errs.Err(); err != nil {
+		t.Fatal("error: unexpected errors: ", err)
+	}
+}
+
+func TestError_Error(t *testing.T) {
+	err := &validate.Error{Path: "/a/0", Code: validate.TypeMismatch, Message: "expected string, got number"}
+
+	if got := err.Error(); got != "#/a/0: expected string, got number (type-mismatch)" {
+		t.Fatal("error: message does not match: ", got)
+	}
+}
+
+func TestJSON_Error(t *testing.T) {
+	if _, err := validate.JSON(collection(t), []byte("{")); err == nil {
+		t.Fatal("error: expected an error for invalid JSON")
+	}
+}
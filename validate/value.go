@@ -0,0 +1,376 @@
+/*
+ * Copyright © 2023 Durudex
+ *
+ * This source code is licensed under the MIT license found in the
+ * LICENSE file in the root directory of this source tree.
+ */
+
+package validate
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/durudex/go-polylang/metadata"
+)
+
+// pointer escapes a property name as a JSON pointer token.
+var pointer = strings.NewReplacer("~", "~0", "/", "~1")
+
+type validator struct {
+	errors Errors
+}
+
+func (v *validator) errorf(path string, code Code, format string, args ...any) {
+	v.errors = append(v.errors, &Error{
+		Path:    path,
+		Code:    code,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) mismatch(path, want string, value any) {
+	v.errorf(path, TypeMismatch, "expected %s, got %s", want, kind(value))
+}
+
+func (v *validator) value(path string, t metadata.Type, value any) error {
+	switch t.Kind {
+	case "primitive":
+		primitive, _, err := t.Primitive()
+		if err != nil {
+			return err
+		}
+
+		v.primitive(path, primitive.Value, value)
+	case "publickey":
+		v.publicKey(path, value)
+	case "record", "foreignrecord":
+		collection := ""
+
+		if t.Kind == "foreignrecord" {
+			rec, _, err := t.ForeignRecord()
+			if err != nil {
+				return err
+			}
+
+			collection = rec.Collection
+		}
+
+		v.reference(path, collection, value)
+	case "array":
+		array, _, err := t.Array()
+		if err != nil {
+			return err
+		}
+
+		rv := reflect.ValueOf(value)
+		if value == nil || rv.Kind() != reflect.Slice || isBytes(value) {
+			v.mismatch(path, "array", value)
+
+			return nil
+		}
+
+		for i := 0; i < rv.Len(); i++ {
+			if err := v.value(path+"/"+strconv.Itoa(i), array.Value, rv.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+	case "map":
+		mp, _, err := t.Map()
+		if err != nil {
+			return err
+		}
+
+		return v.mapValue(path, mp, value)
+	case "object":
+		obj, _, err := t.Object()
+		if err != nil {
+			return err
+		}
+
+		return v.object(path, obj.Fields, value)
+	default:
+		return fmt.Errorf("unknown type kind %q", t.Kind)
+	}
+
+	return nil
+}
+
+func (v *validator) primitive(path string, t metadata.PrimitiveType, value any) {
+	switch t {
+	case metadata.PrimitiveTypeString:
+		if _, ok := value.(string); !ok {
+			v.mismatch(path, "string", value)
+		}
+	case metadata.PrimitiveTypeNumber:
+		if !isNumber(value) {
+			v.mismatch(path, "number", value)
+		}
+	case metadata.PrimitiveTypeBoolean:
+		if _, ok := value.(bool); !ok {
+			v.mismatch(path, "boolean", value)
+		}
+	case metadata.PrimitiveTypeBytes:
+		switch value := value.(type) {
+		case []byte:
+		case string:
+			if _, err := base64.StdEncoding.DecodeString(value); err != nil {
+				v.errorf(path, InvalidBytes, "invalid base64 bytes: %s", err)
+			}
+		default:
+			v.mismatch(path, "bytes", value)
+		}
+	}
+}
+
+// fields returns the values of an object by property name, or false if the
+// value is not an object.
+func fields(value any) (map[string]any, bool) {
+	if obj, ok := value.(map[string]any); ok {
+		return obj, true
+	}
+
+	rv := reflect.ValueOf(value)
+	if value == nil || rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+		return nil, false
+	}
+
+	out := make(map[string]any, rv.Len())
+
+	for iter := rv.MapRange(); iter.Next(); {
+		out[iter.Key().String()] = iter.Value().Interface()
+	}
+
+	return out, true
+}
+
+func (v *validator) object(path string, want []metadata.ObjectField, value any) error {
+	obj, ok := fields(value)
+	if !ok {
+		v.mismatch(path, "object", value)
+
+		return nil
+	}
+
+	known := make(map[string]bool, len(want))
+
+	for _, field := range want {
+		known[field.Name] = true
+		fieldPath := path + "/" + pointer.Replace(field.Name)
+
+		value, ok := obj[field.Name]
+		if !ok || value == nil {
+			if field.Required {
+				v.errorf(fieldPath, MissingProperty, "missing required property %s", field.Name)
+			}
+
+			continue
+		}
+
+		if err := v.value(fieldPath, field.Type, value); err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+	}
+
+	v.unknown(path, obj, known)
+
+	return nil
+}
+
+// unknown reports the properties of an object that are not known, sorted by
+// name.
+func (v *validator) unknown(path string, obj map[string]any, known map[string]bool) {
+	var names []string
+
+	for name := range obj {
+		if !known[name] {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		v.errorf(path+"/"+pointer.Replace(name), UnknownProperty, "unknown property %s", name)
+	}
+}
+
+func (v *validator) mapValue(path string, mp *metadata.Map, value any) error {
+	obj, ok := fields(value)
+	if !ok {
+		v.mismatch(path, "object", value)
+
+		return nil
+	}
+
+	key, ok, err := mp.Key.Primitive()
+	if err != nil {
+		return err
+	}
+
+	if !ok || key.Value != metadata.PrimitiveTypeString && key.Value != metadata.PrimitiveTypeNumber {
+		return fmt.Errorf("invalid map key type %s", mp.Key.Kind)
+	}
+
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		keyPath := path + "/" + pointer.Replace(name)
+
+		if key.Value == metadata.PrimitiveTypeNumber {
+			if _, err := strconv.ParseFloat(name, 64); err != nil {
+				v.errorf(keyPath, TypeMismatch, "expected number key, got %q", name)
+
+				continue
+			}
+		}
+
+		if err := v.value(keyPath, mp.Value, obj[name]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reference checks a reference to a record, whose collectionId must be the
+// name of the collection, if it is not empty, or end with a slash and the
+// name, as in the pattern of the jsonschema package.
+func (v *validator) reference(path, collection string, value any) {
+	obj, ok := fields(value)
+	if !ok {
+		v.mismatch(path, "record reference", value)
+
+		return
+	}
+
+	for _, name := range []string{"collectionId", "id"} {
+		s, ok := obj[name].(string)
+
+		switch {
+		case obj[name] == nil:
+			v.errorf(path+"/"+name, MissingProperty, "missing required property %s", name)
+		case !ok:
+			v.mismatch(path+"/"+name, "string", obj[name])
+		case name == "collectionId" && collection != "" &&
+			s != collection && !strings.HasSuffix(s, "/"+collection):
+			v.errorf(path+"/"+name, InvalidReference,
+				"expected a record of collection %s, got %s", collection, s)
+		}
+	}
+
+	v.unknown(path, obj, map[string]bool{"collectionId": true, "id": true})
+}
+
+// publicKeyMembers are the members of a public key with a fixed value.
+var publicKeyMembers = []struct{ name, value string }{
+	{"kty", "EC"}, {"crv", "secp256k1"}, {"alg", "ES256K"}, {"use", "sig"},
+}
+
+// publicKey checks a public key in the JSON Web Key format, whose coordinates
+// are 32 bytes each.
+func (v *validator) publicKey(path string, value any) {
+	obj, ok := fields(value)
+	if !ok {
+		v.mismatch(path, "public key", value)
+
+		return
+	}
+
+	known := map[string]bool{"x": true, "y": true}
+
+	for _, member := range publicKeyMembers {
+		known[member.name] = true
+
+		if s, _ := obj[member.name].(string); s != member.value {
+			v.errorf(path+"/"+member.name, InvalidPublicKey,
+				"expected %s to be %q, got %s", member.name, member.value, show(obj[member.name]))
+		}
+	}
+
+	for _, name := range []string{"x", "y"} {
+		s, ok := obj[name].(string)
+		if !ok {
+			v.errorf(path+"/"+name, InvalidPublicKey, "expected %s coordinate, got %s", name, show(obj[name]))
+
+			continue
+		}
+
+		if data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "=")); err != nil || len(data) != 32 {
+			v.errorf(path+"/"+name, InvalidPublicKey, "%s coordinate is not 32 base64url encoded bytes", name)
+		}
+	}
+
+	v.unknown(path, obj, known)
+}
+
+// show describes a value in a message, as its kind or quoted if it is a
+// string.
+func show(value any) string {
+	if s, ok := value.(string); ok {
+		return strconv.Quote(s)
+	}
+
+	return kind(value)
+}
+
+func isBytes(value any) bool {
+	_, ok := value.([]byte)
+
+	return ok
+}
+
+func isNumber(value any) bool {
+	if n, ok := value.(json.Number); ok {
+		_, err := n.Float64()
+
+		return err == nil
+	}
+
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// kind returns the JSON kind of a value for messages.
+func kind(value any) string {
+	switch {
+	case value == nil:
+		return "null"
+	case isNumber(value):
+		return "number"
+	case isBytes(value):
+		return "bytes"
+	}
+
+	switch value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	}
+
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}